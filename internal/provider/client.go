@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// engineEditionAzureSQLDatabase is the SERVERPROPERTY('EngineEdition') value
+// reported by Azure SQL Database, which does not support every on-premises
+// ALTER DATABASE option.
+const engineEditionAzureSQLDatabase = 5
+
+// errUnknownProviderConfig is returned when a resource needs the server before
+// Terraform knows the provider configuration, which only happens when the
+// client does not support deferred actions.
+var errUnknownProviderConfig = errors.New("the provider configuration depends on values that are not known until apply; " +
+	"the connection to SQL Server will be made once they are known")
+
+// mssqlClient is handed to resources and data sources as provider data. The
+// connection is opened lazily on the first call to DB, so the provider can
+// be configured with values that only exist once other resources are applied.
+//
+// Statements that must run inside a user database use a pool returned by
+// Database, whose connections are opened against that database. Pooled
+// connections are reset to their login database when they are reused, so
+// switching with USE on a shared pool is not safe, and it is not supported
+// at all on Azure SQL Database.
+type mssqlClient struct {
+	config  connectionConfig
+	unknown bool
+
+	mu            sync.Mutex
+	dbs           map[string]*sql.DB
+	engineEdition int
+}
+
+func newMssqlClient(config connectionConfig) *mssqlClient {
+	return &mssqlClient{
+		config: config,
+		dbs:    map[string]*sql.DB{},
+	}
+}
+
+// DB returns the connection pool for server level statements, connected to
+// master. It connects on first use.
+func (c *mssqlClient) DB(ctx context.Context) (*sql.DB, error) {
+	return c.Database(ctx, "master")
+}
+
+// Database returns a connection pool whose connections are pinned to the
+// given database. Pools are opened on first use and shared afterwards.
+func (c *mssqlClient) Database(ctx context.Context, name string) (*sql.DB, error) {
+	if c.unknown {
+		return nil, errUnknownProviderConfig
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if db, ok := c.dbs[name]; ok {
+		return db, nil
+	}
+
+	db, err := c.open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if name != "master" {
+		// One pool is kept per managed database, so avoid holding on to
+		// idle sessions that would block DROP DATABASE or SINGLE_USER.
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxIdleTime(30 * time.Second)
+	}
+	c.dbs[name] = db
+	return db, nil
+}
+
+// EngineEdition returns SERVERPROPERTY('EngineEdition') for the server. The
+// value is queried once and cached.
+func (c *mssqlClient) EngineEdition(ctx context.Context) (int, error) {
+	db, err := c.DB(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.engineEdition == 0 {
+		var edition int
+		if err := db.QueryRowContext(ctx, "SELECT CAST(SERVERPROPERTY('EngineEdition') AS int)").Scan(&edition); err != nil {
+			return 0, err
+		}
+		c.engineEdition = edition
+	}
+	return c.engineEdition, nil
+}
+
+// Release closes the pool pinned to the given database, if any. It must be
+// called before the database is dropped or renamed so the pool's sessions do
+// not keep it in use.
+func (c *mssqlClient) Release(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if db, ok := c.dbs[name]; ok && name != "master" {
+		db.Close()
+		delete(c.dbs, name)
+	}
+}
+
+// open creates a connection pool for the given database and verifies it.
+func (c *mssqlClient) open(ctx context.Context, database string) (*sql.DB, error) {
+	connString, err := c.config.connString(database)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection configuration: %w", err)
+	}
+
+	db, err := sql.Open(c.config.driverName(), connString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		if isTLSError(err) {
+			return nil, fmt.Errorf("the TLS handshake with %s:%d failed: %w\n\n"+
+				"Check that tls.certificate_path points at the CA that issued the server certificate and that "+
+				"tls.host_name_in_certificate matches its subject. For development servers with self-signed "+
+				"certificates, set tls.trust_server_certificate = true", c.config.Host, c.config.Port, err)
+		}
+		return nil, fmt.Errorf("ping failed: %w", err)
+	}
+	return db, nil
+}
+
+// hasSQLError reports whether err carries any of the given SQL Server error
+// numbers. A single statement can raise several errors, so all of them are
+// checked.
+func hasSQLError(err error, numbers ...int32) bool {
+	var sqlErr mssql.Error
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+	all := sqlErr.All
+	if len(all) == 0 {
+		all = []mssql.Error{sqlErr}
+	}
+	for _, e := range all {
+		for _, n := range numbers {
+			if e.Number == n {
+				return true
+			}
+		}
+	}
+	return false
+}
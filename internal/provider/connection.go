@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/go-mssqldb/azuread"
+)
+
+// Azure AD / Entra ID authentication modes supported by the azure_auth block.
+const (
+	azureAuthClientSecret      = "client_secret"
+	azureAuthClientCertificate = "client_certificate"
+	azureAuthManagedIdentity   = "managed_identity"
+	azureAuthAccessToken       = "access_token"
+)
+
+// connectionConfig holds the resolved provider settings used to build a DSN.
+type connectionConfig struct {
+	Host     string
+	Port     int32
+	User     string
+	Password string
+	Azure    *azureAuthConfig
+	TLS      *tlsConfig
+}
+
+// tlsConfig holds the resolved tls block settings. Empty values leave the
+// driver defaults in place.
+type tlsConfig struct {
+	Encrypt                string
+	TrustServerCertificate *bool
+	CertificatePath        string
+	HostNameInCertificate  string
+}
+
+// azureAuthConfig holds the resolved azure_auth block settings.
+type azureAuthConfig struct {
+	Mode                string
+	TenantId            string
+	ClientId            string
+	ClientSecret        string
+	CertificatePath     string
+	CertificatePassword string
+	ResourceId          string
+	AccessToken         string
+}
+
+// driverName returns the database/sql driver that understands the DSN
+// produced by connString.
+func (c connectionConfig) driverName() string {
+	if c.Azure != nil {
+		return azuread.DriverName
+	}
+	return "sqlserver"
+}
+
+// connString builds a sqlserver:// DSN for the given database. Credentials are
+// URL encoded so passwords and secrets may contain any character.
+func (c connectionConfig) connString(database string) (string, error) {
+	query := url.Values{}
+	query.Set("database", database)
+
+	u := &url.URL{
+		Scheme: "sqlserver",
+		Host:   fmt.Sprintf("%s:%d", c.Host, c.Port),
+	}
+
+	if c.TLS != nil {
+		if err := c.TLS.apply(query); err != nil {
+			return "", err
+		}
+	}
+
+	if c.Azure == nil {
+		u.User = url.UserPassword(c.User, c.Password)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	switch c.Azure.Mode {
+	case azureAuthClientSecret:
+		if c.Azure.ClientId == "" || c.Azure.ClientSecret == "" {
+			return "", fmt.Errorf("azure_auth mode %q requires client_id and client_secret", c.Azure.Mode)
+		}
+		query.Set("fedauth", azuread.ActiveDirectoryServicePrincipal)
+		u.User = url.UserPassword(azureClientUser(c.Azure), c.Azure.ClientSecret)
+	case azureAuthClientCertificate:
+		if c.Azure.ClientId == "" || c.Azure.CertificatePath == "" {
+			return "", fmt.Errorf("azure_auth mode %q requires client_id and certificate_path", c.Azure.Mode)
+		}
+		query.Set("fedauth", azuread.ActiveDirectoryServicePrincipal)
+		query.Set("clientcertpath", c.Azure.CertificatePath)
+		// go-mssqldb reads the certificate password from the password field.
+		u.User = url.UserPassword(azureClientUser(c.Azure), c.Azure.CertificatePassword)
+	case azureAuthManagedIdentity:
+		query.Set("fedauth", azuread.ActiveDirectoryManagedIdentity)
+		if c.Azure.ResourceId != "" {
+			query.Set("resource id", c.Azure.ResourceId)
+		} else if c.Azure.ClientId != "" {
+			// A user-assigned identity is selected through the user id.
+			u.User = url.User(c.Azure.ClientId)
+		}
+	case azureAuthAccessToken:
+		if c.Azure.AccessToken == "" {
+			return "", fmt.Errorf("azure_auth mode %q requires access_token", c.Azure.Mode)
+		}
+		query.Set("fedauth", azuread.ActiveDirectoryServicePrincipalAccessToken)
+		u.User = url.UserPassword("", c.Azure.AccessToken)
+	default:
+		return "", fmt.Errorf("unsupported azure_auth mode %q, expected one of %q, %q, %q or %q",
+			c.Azure.Mode, azureAuthClientSecret, azureAuthClientCertificate, azureAuthManagedIdentity, azureAuthAccessToken)
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// azureClientUser returns the client id in the client_id@tenant_id form
+// expected by the azuresql driver. The tenant is optional, in which case the
+// driver uses the tenant advertised by the server.
+func azureClientUser(a *azureAuthConfig) string {
+	if a.TenantId == "" {
+		return a.ClientId
+	}
+	return a.ClientId + "@" + a.TenantId
+}
+
+// apply adds the TLS settings to the DSN query parameters.
+func (t *tlsConfig) apply(query url.Values) error {
+	if t.Encrypt != "" {
+		switch t.Encrypt {
+		case "strict", "true", "false", "disable":
+			query.Set("encrypt", t.Encrypt)
+		default:
+			return fmt.Errorf("unsupported tls encrypt value %q, expected one of \"strict\", \"true\", \"false\" or \"disable\"", t.Encrypt)
+		}
+	}
+	if t.TrustServerCertificate != nil {
+		query.Set("TrustServerCertificate", strconv.FormatBool(*t.TrustServerCertificate))
+	}
+	if t.CertificatePath != "" {
+		query.Set("certificate", t.CertificatePath)
+	}
+	if t.HostNameInCertificate != "" {
+		query.Set("hostNameInCertificate", t.HostNameInCertificate)
+	}
+	return nil
+}
+
+// isTLSError reports whether err was raised while negotiating encryption
+// with the server.
+func isTLSError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var certErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "TLS Handshake") || strings.Contains(msg, "x509:")
+}
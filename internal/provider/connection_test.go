@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestConnectionConfigConnString(t *testing.T) {
+	cases := map[string]struct {
+		config       connectionConfig
+		wantDriver   string
+		wantUser     string
+		wantPassword string
+		wantParams   map[string]string
+		wantErr      bool
+	}{
+		"sql auth": {
+			config:       connectionConfig{Host: "localhost", Port: 1433, User: "sa", Password: "p@ss:w/rd?"},
+			wantDriver:   "sqlserver",
+			wantUser:     "sa",
+			wantPassword: "p@ss:w/rd?",
+			wantParams:   map[string]string{"database": "master"},
+		},
+		"client secret": {
+			config: connectionConfig{Host: "srv.database.windows.net", Port: 1433, Azure: &azureAuthConfig{
+				Mode: azureAuthClientSecret, TenantId: "tenant", ClientId: "client", ClientSecret: "secret",
+			}},
+			wantDriver:   "azuresql",
+			wantUser:     "client@tenant",
+			wantPassword: "secret",
+			wantParams:   map[string]string{"database": "master", "fedauth": "ActiveDirectoryServicePrincipal"},
+		},
+		"client secret missing secret": {
+			config:  connectionConfig{Azure: &azureAuthConfig{Mode: azureAuthClientSecret, ClientId: "client"}},
+			wantErr: true,
+		},
+		"client certificate": {
+			config: connectionConfig{Host: "srv", Port: 1433, Azure: &azureAuthConfig{
+				Mode: azureAuthClientCertificate, ClientId: "client", CertificatePath: "/tmp/sp.pfx", CertificatePassword: "pfx",
+			}},
+			wantDriver:   "azuresql",
+			wantUser:     "client",
+			wantPassword: "pfx",
+			wantParams:   map[string]string{"fedauth": "ActiveDirectoryServicePrincipal", "clientcertpath": "/tmp/sp.pfx"},
+		},
+		"managed identity": {
+			config: connectionConfig{Host: "srv", Port: 1433, Azure: &azureAuthConfig{
+				Mode: azureAuthManagedIdentity, ClientId: "uami",
+			}},
+			wantDriver: "azuresql",
+			wantUser:   "uami",
+			wantParams: map[string]string{"fedauth": "ActiveDirectoryManagedIdentity"},
+		},
+		"access token": {
+			config: connectionConfig{Host: "srv", Port: 1433, Azure: &azureAuthConfig{
+				Mode: azureAuthAccessToken, AccessToken: "eyJ0eXAi",
+			}},
+			wantDriver:   "azuresql",
+			wantPassword: "eyJ0eXAi",
+			wantParams:   map[string]string{"fedauth": "ActiveDirectoryServicePrincipalAccessToken"},
+		},
+		"tls": {
+			config: connectionConfig{Host: "localhost", Port: 1433, User: "sa", Password: "pw", TLS: &tlsConfig{
+				Encrypt: "strict", TrustServerCertificate: new(bool), CertificatePath: "/etc/ssl/ca.pem", HostNameInCertificate: "sql.internal",
+			}},
+			wantDriver:   "sqlserver",
+			wantUser:     "sa",
+			wantPassword: "pw",
+			wantParams: map[string]string{
+				"encrypt":                "strict",
+				"TrustServerCertificate": "false",
+				"certificate":            "/etc/ssl/ca.pem",
+				"hostNameInCertificate":  "sql.internal",
+			},
+		},
+		"tls invalid encrypt": {
+			config:  connectionConfig{TLS: &tlsConfig{Encrypt: "always"}},
+			wantErr: true,
+		},
+		"unknown mode": {
+			config:  connectionConfig{Azure: &azureAuthConfig{Mode: "kerberos"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dsn, err := tc.config.connString("master")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got DSN %q", dsn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := tc.config.driverName(); got != tc.wantDriver {
+				t.Errorf("driver: got %q, want %q", got, tc.wantDriver)
+			}
+
+			u, err := url.Parse(dsn)
+			if err != nil {
+				t.Fatalf("DSN %q does not parse: %s", dsn, err)
+			}
+			var user, password string
+			if u.User != nil {
+				user = u.User.Username()
+				password, _ = u.User.Password()
+			}
+			if user != tc.wantUser {
+				t.Errorf("user: got %q, want %q", user, tc.wantUser)
+			}
+			if password != tc.wantPassword {
+				t.Errorf("password: got %q, want %q", password, tc.wantPassword)
+			}
+			for k, want := range tc.wantParams {
+				if got := u.Query().Get(k); got != want {
+					t.Errorf("param %s: got %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// serviceObjectiveElasticPool is the service objective Azure SQL Database
+// reports for databases in an elastic pool.
+const serviceObjectiveElasticPool = "ElasticPool"
+
+// serviceObjectivePollInterval is how often a scaling operation is checked
+// for completion.
+var serviceObjectivePollInterval = 15 * time.Second
+
+// bytesPerGb converts MaxSizeInBytes to max_size_gb.
+const bytesPerGb = 1024 * 1024 * 1024
+
+// serviceOptions returns the edition options of plan that differ from state,
+// for CREATE DATABASE (...) and ALTER DATABASE MODIFY (...). state is nil on
+// create.
+func serviceOptions(plan, state *databaseResourceModel) ([]string, error) {
+	changed := func(planned, current attr.Value) bool {
+		return known(planned) && (state == nil || !planned.Equal(current))
+	}
+	var current databaseResourceModel
+	if state != nil {
+		current = *state
+	}
+
+	var options []string
+	if changed(plan.Edition, current.Edition) {
+		if err := validateKeyword("edition", plan.Edition.ValueString()); err != nil {
+			return nil, err
+		}
+		options = append(options, fmt.Sprintf("EDITION = '%s'", plan.Edition.ValueString()))
+	}
+	if changed(plan.ElasticPool, current.ElasticPool) {
+		options = append(options, fmt.Sprintf("SERVICE_OBJECTIVE = ELASTIC_POOL (name = %s)", quoteIdentifier(plan.ElasticPool.ValueString())))
+	} else if plan.ElasticPool.IsNull() && changed(plan.ServiceObjective, current.ServiceObjective) {
+		if err := validateKeyword("service objective", plan.ServiceObjective.ValueString()); err != nil {
+			return nil, err
+		}
+		options = append(options, fmt.Sprintf("SERVICE_OBJECTIVE = '%s'", plan.ServiceObjective.ValueString()))
+	}
+	if changed(plan.MaxSizeGb, current.MaxSizeGb) {
+		options = append(options, fmt.Sprintf("MAXSIZE = %d GB", plan.MaxSizeGb.ValueInt64()))
+	}
+	return options, nil
+}
+
+// readServiceObjective populates the Azure SQL Database service tier of the
+// database named in data from sys.database_service_objectives.
+func readServiceObjective(ctx context.Context, db *sql.DB, data *databaseResourceModel) error {
+	var (
+		edition, serviceObjective string
+		elasticPool               sql.NullString
+		maxSizeBytes              sql.NullInt64
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT so.edition, so.service_objective, so.elastic_pool_name,
+			CAST(DATABASEPROPERTYEX(d.name, 'MaxSizeInBytes') AS bigint)
+		FROM sys.database_service_objectives so
+		JOIN sys.databases d ON d.database_id = so.database_id
+		WHERE d.name = @p1`, data.Name.ValueString()).Scan(&edition, &serviceObjective, &elasticPool, &maxSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	data.Edition = keepCase(data.Edition, edition)
+	data.ServiceObjective = keepCase(data.ServiceObjective, serviceObjective)
+	data.ElasticPool = types.StringNull()
+	if elasticPool.Valid {
+		data.ElasticPool = keepCase(data.ElasticPool, elasticPool.String)
+	}
+	if maxSizeBytes.Valid {
+		data.MaxSizeGb = types.Int64Value(maxSizeBytes.Int64 / bytesPerGb)
+	}
+	return nil
+}
+
+// waitForServiceObjective polls until the service tier of the database
+// matches plan. Scaling an Azure SQL Database continues in the background
+// after ALTER DATABASE returns.
+func waitForServiceObjective(ctx context.Context, db *sql.DB, plan *databaseResourceModel) error {
+	for {
+		current := databaseResourceModel{Name: plan.Name}
+		if err := readServiceObjective(ctx, db, &current); err != nil {
+			return err
+		}
+		if serviceObjectiveReached(plan, &current) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the database to reach its service objective: %w", ctx.Err())
+		case <-time.After(serviceObjectivePollInterval):
+		}
+	}
+}
+
+func serviceObjectiveReached(plan, current *databaseResourceModel) bool {
+	matches := func(planned, actual types.String) bool {
+		return !known(planned) || strings.EqualFold(planned.ValueString(), actual.ValueString())
+	}
+	if known(plan.ElasticPool) {
+		return matches(plan.ElasticPool, current.ElasticPool) && matches(plan.Edition, current.Edition)
+	}
+	return current.ElasticPool.IsNull() && matches(plan.ServiceObjective, current.ServiceObjective) && matches(plan.Edition, current.Edition)
+}
+
+// planServiceObjective adjusts the planned service tier. Configuring a
+// service objective without an elastic pool moves the database out of its
+// pool, and the attributes the configuration leaves to the server become
+// unknown when another of them changes, since Azure picks new defaults for
+// them.
+func planServiceObjective(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, plan, state *databaseResourceModel) {
+	var config databaseResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.ElasticPool.IsNull() && known(config.ServiceObjective) &&
+		!strings.EqualFold(config.ServiceObjective.ValueString(), serviceObjectiveElasticPool) {
+		plan.ElasticPool = types.StringNull()
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("elastic_pool"), plan.ElasticPool)...)
+	}
+
+	if plan.Edition.Equal(state.Edition) && plan.ServiceObjective.Equal(state.ServiceObjective) &&
+		plan.MaxSizeGb.Equal(state.MaxSizeGb) && plan.ElasticPool.Equal(state.ElasticPool) {
+		return
+	}
+
+	if config.Edition.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("edition"), types.StringUnknown())...)
+	}
+	if config.ServiceObjective.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("service_objective"), types.StringUnknown())...)
+	}
+	if config.MaxSizeGb.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("max_size_gb"), types.Int64Unknown())...)
+	}
+}
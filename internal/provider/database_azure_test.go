@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestServiceOptions(t *testing.T) {
+	state := &databaseResourceModel{
+		Edition:          types.StringValue("GeneralPurpose"),
+		ServiceObjective: types.StringValue("GP_Gen5_2"),
+		MaxSizeGb:        types.Int64Value(32),
+		ElasticPool:      types.StringNull(),
+	}
+	cases := map[string]struct {
+		plan  databaseResourceModel
+		state *databaseResourceModel
+		want  []string
+	}{
+		"create": {
+			plan: databaseResourceModel{
+				Edition:          types.StringValue("GeneralPurpose"),
+				ServiceObjective: types.StringValue("GP_Gen5_2"),
+				MaxSizeGb:        types.Int64Value(32),
+				ElasticPool:      types.StringNull(),
+			},
+			want: []string{"EDITION = 'GeneralPurpose'", "SERVICE_OBJECTIVE = 'GP_Gen5_2'", "MAXSIZE = 32 GB"},
+		},
+		"create with defaults": {
+			plan: databaseResourceModel{
+				Edition:          types.StringUnknown(),
+				ServiceObjective: types.StringUnknown(),
+				MaxSizeGb:        types.Int64Unknown(),
+				ElasticPool:      types.StringUnknown(),
+			},
+		},
+		"scale up": {
+			plan: databaseResourceModel{
+				Edition:          types.StringValue("GeneralPurpose"),
+				ServiceObjective: types.StringValue("GP_Gen5_4"),
+				MaxSizeGb:        types.Int64Unknown(),
+				ElasticPool:      types.StringNull(),
+			},
+			state: state,
+			want:  []string{"SERVICE_OBJECTIVE = 'GP_Gen5_4'"},
+		},
+		"move into pool": {
+			plan: databaseResourceModel{
+				Edition:          types.StringUnknown(),
+				ServiceObjective: types.StringUnknown(),
+				MaxSizeGb:        types.Int64Value(32),
+				ElasticPool:      types.StringValue("pool]1"),
+			},
+			state: state,
+			want:  []string{"SERVICE_OBJECTIVE = ELASTIC_POOL (name = [pool]]1])"},
+		},
+	}
+	for name, tc := range cases {
+		got, err := serviceOptions(&tc.plan, tc.state)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: serviceOptions() = %q, want %q", name, got, tc.want)
+		}
+	}
+
+	_, err := serviceOptions(&databaseResourceModel{Edition: types.StringValue("Standard'; DROP DATABASE x; --")}, nil)
+	if err == nil {
+		t.Error("serviceOptions() accepted an invalid edition")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// databaseChangeTrackingModel maps the change_tracking block of
+// mssql_database.
+type databaseChangeTrackingModel struct {
+	Retention     types.Int64  `tfsdk:"retention"`
+	RetentionUnit types.String `tfsdk:"retention_unit"`
+	AutoCleanup   types.Bool   `tfsdk:"auto_cleanup"`
+}
+
+func databaseChangeTrackingBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Enables change tracking on the database. Removing the block disables it, which fails " +
+			"while tables still have change tracking enabled.",
+		Attributes: map[string]schema.Attribute{
+			"retention": schema.Int64Attribute{
+				MarkdownDescription: "How long change tracking information is kept, in `retention_unit`. Defaults to `2`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(2),
+			},
+			"retention_unit": schema.StringAttribute{
+				MarkdownDescription: "Unit of `retention`: `DAYS`, `HOURS` or `MINUTES`. Defaults to `DAYS`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("DAYS"),
+			},
+			"auto_cleanup": schema.BoolAttribute{
+				MarkdownDescription: "Whether expired change tracking information is removed automatically. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+		},
+	}
+}
+
+func validateChangeTracking(diags *diag.Diagnostics, p path.Path, ct *databaseChangeTrackingModel) {
+	if ct == nil {
+		return
+	}
+	validateOneOf(diags, p.AtName("retention_unit"), ct.RetentionUnit, "DAYS", "HOURS", "MINUTES")
+}
+
+// changeTrackingStatement returns the SET clause that moves change tracking
+// from state to plan, or "" when nothing changed.
+func changeTrackingStatement(plan, state *databaseChangeTrackingModel) (string, error) {
+	if plan == nil {
+		if state == nil {
+			return "", nil
+		}
+		return "CHANGE_TRACKING = OFF", nil
+	}
+	if state != nil && plan.Retention.Equal(state.Retention) &&
+		strings.EqualFold(plan.RetentionUnit.ValueString(), state.RetentionUnit.ValueString()) &&
+		plan.AutoCleanup.Equal(state.AutoCleanup) {
+		return "", nil
+	}
+
+	unit := strings.ToUpper(plan.RetentionUnit.ValueString())
+	if err := validateKeyword("retention unit", unit); err != nil {
+		return "", err
+	}
+	options := fmt.Sprintf("(CHANGE_RETENTION = %d %s, AUTO_CLEANUP = %s)",
+		plan.Retention.ValueInt64(), unit, onOff(plan.AutoCleanup.ValueBool()))
+	if state == nil {
+		return "CHANGE_TRACKING = ON " + options, nil
+	}
+	return "CHANGE_TRACKING " + options, nil
+}
+
+// readChangeTracking returns the change tracking settings of the database
+// from sys.change_tracking_databases, or nil when it is disabled.
+func readChangeTracking(ctx context.Context, db *sql.DB, database string, prior *databaseChangeTrackingModel) (*databaseChangeTrackingModel, error) {
+	var (
+		retention   int64
+		unit        string
+		autoCleanup bool
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT retention_period, retention_period_units_desc, is_auto_cleanup_on
+		FROM sys.change_tracking_databases
+		WHERE database_id = DB_ID(@p1)`, database).Scan(&retention, &unit, &autoCleanup)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var priorUnit types.String
+	if prior != nil {
+		priorUnit = prior.RetentionUnit
+	}
+	return &databaseChangeTrackingModel{
+		Retention:     types.Int64Value(retention),
+		RetentionUnit: keepCase(priorUnit, unit),
+		AutoCleanup:   types.BoolValue(autoCleanup),
+	}, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestChangeTrackingStatement(t *testing.T) {
+	current := &databaseChangeTrackingModel{
+		Retention:     types.Int64Value(2),
+		RetentionUnit: types.StringValue("DAYS"),
+		AutoCleanup:   types.BoolValue(true),
+	}
+	cases := map[string]struct {
+		plan  *databaseChangeTrackingModel
+		state *databaseChangeTrackingModel
+		want  string
+	}{
+		"not managed": {},
+		"enable": {
+			plan: current,
+			want: "CHANGE_TRACKING = ON (CHANGE_RETENTION = 2 DAYS, AUTO_CLEANUP = ON)",
+		},
+		"unchanged": {
+			plan: &databaseChangeTrackingModel{
+				Retention:     types.Int64Value(2),
+				RetentionUnit: types.StringValue("days"),
+				AutoCleanup:   types.BoolValue(true),
+			},
+			state: current,
+		},
+		"change retention": {
+			plan: &databaseChangeTrackingModel{
+				Retention:     types.Int64Value(12),
+				RetentionUnit: types.StringValue("hours"),
+				AutoCleanup:   types.BoolValue(false),
+			},
+			state: current,
+			want:  "CHANGE_TRACKING (CHANGE_RETENTION = 12 HOURS, AUTO_CLEANUP = OFF)",
+		},
+		"disable": {
+			state: current,
+			want:  "CHANGE_TRACKING = OFF",
+		},
+	}
+	for name, tc := range cases {
+		got, err := changeTrackingStatement(tc.plan, tc.state)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: changeTrackingStatement() = %q, want %q", name, got, tc.want)
+		}
+	}
+
+	_, err := changeTrackingStatement(&databaseChangeTrackingModel{
+		Retention:     types.Int64Value(2),
+		RetentionUnit: types.StringValue("DAYS); DROP DATABASE x; --"),
+		AutoCleanup:   types.BoolValue(true),
+	}, nil)
+	if err == nil {
+		t.Error("changeTrackingStatement() accepted an invalid retention unit")
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Values of sys.database_files.type.
+const (
+	databaseFileTypeRows       = 0
+	databaseFileTypeLog        = 1
+	databaseFileTypeFilestream = 2
+)
+
+// primaryDataSpaceId is the data_space_id of the PRIMARY filegroup.
+const primaryDataSpaceId = 1
+
+// unlimitedLogMaxSize is the max_size SQL Server reports, in 8 KB pages, for
+// log files created with MAXSIZE = UNLIMITED.
+const unlimitedLogMaxSize = 268435456
+
+// databaseFileModel maps a data_file, log_file or file block.
+type databaseFileModel struct {
+	Name          types.String `tfsdk:"name"`
+	FileName      types.String `tfsdk:"filename"`
+	SizeMb        types.Int64  `tfsdk:"size_mb"`
+	MaxSizeMb     types.Int64  `tfsdk:"max_size_mb"`
+	GrowthMb      types.Int64  `tfsdk:"growth_mb"`
+	GrowthPercent types.Int64  `tfsdk:"growth_percent"`
+}
+
+// databaseFileBlock returns the nested block schema shared by database and
+// filegroup files.
+func databaseFileBlock(description string) schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		MarkdownDescription: description,
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Logical file name.",
+					Required:            true,
+				},
+				"filename": schema.StringAttribute{
+					MarkdownDescription: "Path of the file on the server. Moving an existing file is not supported.",
+					Required:            true,
+				},
+				"size_mb": schema.Int64Attribute{
+					MarkdownDescription: "Size of the file in MB. Files can only grow in place.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.Int64{
+						int64planmodifier.UseStateForUnknown(),
+					},
+				},
+				"max_size_mb": schema.Int64Attribute{
+					MarkdownDescription: "Maximum size of the file in MB, or `-1` for unlimited.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.Int64{
+						int64planmodifier.UseStateForUnknown(),
+					},
+				},
+				// The growth attributes are not carried over from state, as
+				// setting one of them clears the other.
+				"growth_mb": schema.Int64Attribute{
+					MarkdownDescription: "Automatic growth increment in MB. `0` disables automatic growth. Conflicts with `growth_percent`.",
+					Optional:            true,
+					Computed:            true,
+				},
+				"growth_percent": schema.Int64Attribute{
+					MarkdownDescription: "Automatic growth increment as a percentage of the file size. Conflicts with `growth_mb`.",
+					Optional:            true,
+					Computed:            true,
+				},
+			},
+		},
+	}
+}
+
+// validateDatabaseFiles checks file blocks for settings that conflict.
+// sized is false for FILESTREAM and memory-optimized containers, which have
+// no size settings.
+func validateDatabaseFiles(diags *diag.Diagnostics, p path.Path, files []databaseFileModel, sized bool) {
+	for i, f := range files {
+		fp := p.AtListIndex(i)
+		if !sized {
+			if !f.SizeMb.IsNull() || !f.MaxSizeMb.IsNull() || !f.GrowthMb.IsNull() || !f.GrowthPercent.IsNull() {
+				diags.AddAttributeError(fp, "Invalid file settings",
+					"FILESTREAM and memory-optimized containers do not take size_mb, max_size_mb, growth_mb or growth_percent.")
+			}
+			continue
+		}
+		if !f.GrowthMb.IsNull() && !f.GrowthPercent.IsNull() {
+			diags.AddAttributeError(fp, "Conflicting file growth settings", "Only one of growth_mb and growth_percent can be set.")
+		}
+		if !f.MaxSizeMb.IsNull() && !f.MaxSizeMb.IsUnknown() && f.MaxSizeMb.ValueInt64() < -1 {
+			diags.AddAttributeError(fp.AtName("max_size_mb"), "Invalid max_size_mb", "max_size_mb must be -1 (unlimited) or a size in MB.")
+		}
+	}
+}
+
+// fileSpec renders a file block as a <filespec> clause for CREATE DATABASE
+// and ALTER DATABASE ADD FILE.
+func fileSpec(f databaseFileModel) string {
+	parts := []string{
+		"NAME = " + quoteString(f.Name.ValueString()),
+		"FILENAME = " + quoteString(f.FileName.ValueString()),
+	}
+	if known(f.SizeMb) {
+		parts = append(parts, fmt.Sprintf("SIZE = %dMB", f.SizeMb.ValueInt64()))
+	}
+	if known(f.MaxSizeMb) {
+		parts = append(parts, "MAXSIZE = "+maxSizeClause(f.MaxSizeMb.ValueInt64()))
+	}
+	if known(f.GrowthMb) {
+		parts = append(parts, fmt.Sprintf("FILEGROWTH = %dMB", f.GrowthMb.ValueInt64()))
+	} else if known(f.GrowthPercent) {
+		parts = append(parts, fmt.Sprintf("FILEGROWTH = %d%%", f.GrowthPercent.ValueInt64()))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// fileSpecs renders a list of file blocks as comma separated <filespec>
+// clauses.
+func fileSpecs(files []databaseFileModel) string {
+	specs := make([]string, 0, len(files))
+	for _, f := range files {
+		specs = append(specs, fileSpec(f))
+	}
+	return strings.Join(specs, ", ")
+}
+
+func maxSizeClause(mb int64) string {
+	if mb < 0 {
+		return "UNLIMITED"
+	}
+	return fmt.Sprintf("%dMB", mb)
+}
+
+// readDatabaseFiles returns the files of the given type and data space from
+// sys.database_files. db must be connected to the database that owns them.
+func readDatabaseFiles(ctx context.Context, db *sql.DB, fileType, dataSpaceId int) ([]databaseFileModel, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT name, physical_name, size, max_size, growth, is_percent_growth
+		FROM sys.database_files
+		WHERE type = @p1 AND data_space_id = @p2
+		ORDER BY file_id`, fileType, dataSpaceId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []databaseFileModel{}
+	for rows.Next() {
+		var (
+			name, physicalName    string
+			size, maxSize, growth int64
+			isPercentGrowth       bool
+		)
+		if err := rows.Scan(&name, &physicalName, &size, &maxSize, &growth, &isPercentGrowth); err != nil {
+			return nil, err
+		}
+		f := databaseFileModel{
+			Name:          types.StringValue(name),
+			FileName:      types.StringValue(physicalName),
+			SizeMb:        types.Int64Value(pagesToMb(size)),
+			MaxSizeMb:     types.Int64Value(-1),
+			GrowthMb:      types.Int64Null(),
+			GrowthPercent: types.Int64Null(),
+		}
+		if maxSize != -1 && !(fileType == databaseFileTypeLog && maxSize == unlimitedLogMaxSize) {
+			f.MaxSizeMb = types.Int64Value(pagesToMb(maxSize))
+		}
+		if isPercentGrowth {
+			f.GrowthPercent = types.Int64Value(growth)
+		} else {
+			f.GrowthMb = types.Int64Value(pagesToMb(growth))
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// pagesToMb converts a size in 8 KB pages to MB.
+func pagesToMb(pages int64) int64 {
+	return pages * 8 / 1024
+}
+
+// mergeDatabaseFiles returns the files read from the server in the order of
+// prior, followed by any files prior does not know about so they show up as
+// drift. Logical names and
+// paths keep the case used in prior.
+func mergeDatabaseFiles(prior, actual []databaseFileModel) []databaseFileModel {
+	byName := map[string]databaseFileModel{}
+	for _, f := range actual {
+		byName[strings.ToLower(f.Name.ValueString())] = f
+	}
+
+	merged := make([]databaseFileModel, 0, len(actual))
+	for _, p := range prior {
+		key := strings.ToLower(p.Name.ValueString())
+		f, ok := byName[key]
+		if !ok {
+			continue
+		}
+		f.Name = keepCase(p.Name, f.Name.ValueString())
+		f.FileName = keepCase(p.FileName, f.FileName.ValueString())
+		merged = append(merged, f)
+		delete(byName, key)
+	}
+	for _, f := range actual {
+		if _, ok := byName[strings.ToLower(f.Name.ValueString())]; ok {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// alterDatabaseFiles adds, modifies and removes files of one kind so that
+// the database matches planned. actual holds the files currently on the
+// server; files missing from planned are emptied into the remaining files of
+// their filegroup and removed. addFiles returns the ALTER DATABASE action that
+// adds the given file specs, such as "ADD LOG FILE (...)". dbConn must be
+// connected to the database.
+func alterDatabaseFiles(ctx context.Context, db, dbConn *sql.DB, database string, p path.Path, planned, actual []databaseFileModel, addFiles func(specs string) string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	name := quoteIdentifier(database)
+
+	current := map[string]databaseFileModel{}
+	for _, f := range actual {
+		current[strings.ToLower(f.Name.ValueString())] = f
+	}
+
+	var added []databaseFileModel
+	for i, f := range planned {
+		fp := p.AtListIndex(i)
+		existing, ok := current[strings.ToLower(f.Name.ValueString())]
+		if !ok {
+			added = append(added, f)
+			continue
+		}
+		if !strings.EqualFold(f.FileName.ValueString(), existing.FileName.ValueString()) {
+			diags.AddAttributeError(fp.AtName("filename"), "Cannot move database file",
+				fmt.Sprintf("File %q is at %q. Moving a database file requires taking the database offline "+
+					"and moving it on disk, which the provider does not do. Move the file manually, or keep the "+
+					"existing path.", existing.Name.ValueString(), existing.FileName.ValueString()))
+			return diags
+		}
+
+		logical := "NAME = " + quoteString(existing.Name.ValueString())
+		// MODIFY FILE only accepts one property at a time.
+		var changes []string
+		if known(f.SizeMb) && f.SizeMb.ValueInt64() != existing.SizeMb.ValueInt64() {
+			changes = append(changes, fmt.Sprintf("SIZE = %dMB", f.SizeMb.ValueInt64()))
+		}
+		if known(f.MaxSizeMb) && f.MaxSizeMb.ValueInt64() != existing.MaxSizeMb.ValueInt64() {
+			changes = append(changes, "MAXSIZE = "+maxSizeClause(f.MaxSizeMb.ValueInt64()))
+		}
+		if known(f.GrowthMb) && !f.GrowthMb.Equal(existing.GrowthMb) {
+			changes = append(changes, fmt.Sprintf("FILEGROWTH = %dMB", f.GrowthMb.ValueInt64()))
+		} else if known(f.GrowthPercent) && !f.GrowthPercent.Equal(existing.GrowthPercent) {
+			changes = append(changes, fmt.Sprintf("FILEGROWTH = %d%%", f.GrowthPercent.ValueInt64()))
+		}
+		for _, change := range changes {
+			_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY FILE (%s, %s)", name, logical, change))
+			if err != nil {
+				detail := err.Error()
+				if hasSQLError(err, 5039) {
+					detail += "\n\nFiles can only grow in place. Shrink the file with DBCC SHRINKFILE, then apply again."
+				}
+				diags.AddAttributeError(fp, "Error modifying database file", detail)
+				return diags
+			}
+		}
+	}
+
+	if len(added) > 0 {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s %s", name, addFiles(fileSpecs(added))))
+		if err != nil {
+			diags.AddAttributeError(p, "Error adding database files", err.Error())
+			return diags
+		}
+	}
+
+	planNames := map[string]bool{}
+	for _, f := range planned {
+		planNames[strings.ToLower(f.Name.ValueString())] = true
+	}
+	for _, f := range actual {
+		if planNames[strings.ToLower(f.Name.ValueString())] {
+			continue
+		}
+		// Move the data to the other files of the filegroup so the file
+		// can be removed.
+		if _, err := dbConn.ExecContext(ctx, "DBCC SHRINKFILE (@p1, EMPTYFILE)", f.Name.ValueString()); err != nil {
+			diags.AddAttributeError(p, "Error emptying database file", fmt.Sprintf("File %q: %s", f.Name.ValueString(), err.Error()))
+			return diags
+		}
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s REMOVE FILE %s", name, quoteIdentifier(f.Name.ValueString())))
+		if err != nil {
+			diags.AddAttributeError(p, "Error removing database file", fmt.Sprintf("File %q: %s", f.Name.ValueString(), err.Error()))
+			return diags
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFileSpec(t *testing.T) {
+	cases := map[string]struct {
+		file databaseFileModel
+		want string
+	}{
+		"name and path only": {
+			file: databaseFileModel{
+				Name:          types.StringValue("app"),
+				FileName:      types.StringValue(`D:\data\app.mdf`),
+				SizeMb:        types.Int64Unknown(),
+				MaxSizeMb:     types.Int64Unknown(),
+				GrowthMb:      types.Int64Unknown(),
+				GrowthPercent: types.Int64Unknown(),
+			},
+			want: `(NAME = N'app', FILENAME = N'D:\data\app.mdf')`,
+		},
+		"sizes in MB": {
+			file: databaseFileModel{
+				Name:          types.StringValue("app_log"),
+				FileName:      types.StringValue("/var/opt/mssql/data/app_log.ldf"),
+				SizeMb:        types.Int64Value(64),
+				MaxSizeMb:     types.Int64Value(-1),
+				GrowthMb:      types.Int64Value(128),
+				GrowthPercent: types.Int64Null(),
+			},
+			want: "(NAME = N'app_log', FILENAME = N'/var/opt/mssql/data/app_log.ldf', SIZE = 64MB, MAXSIZE = UNLIMITED, FILEGROWTH = 128MB)",
+		},
+		"percent growth and quoting": {
+			file: databaseFileModel{
+				Name:          types.StringValue("o'brien"),
+				FileName:      types.StringValue("/data/o'brien.ndf"),
+				SizeMb:        types.Int64Null(),
+				MaxSizeMb:     types.Int64Value(1024),
+				GrowthMb:      types.Int64Null(),
+				GrowthPercent: types.Int64Value(10),
+			},
+			want: "(NAME = N'o''brien', FILENAME = N'/data/o''brien.ndf', MAXSIZE = 1024MB, FILEGROWTH = 10%)",
+		},
+	}
+	for name, tc := range cases {
+		if got := fileSpec(tc.file); got != tc.want {
+			t.Errorf("%s: fileSpec() = %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestMergeDatabaseFiles(t *testing.T) {
+	file := func(name, path string) databaseFileModel {
+		return databaseFileModel{Name: types.StringValue(name), FileName: types.StringValue(path)}
+	}
+	prior := []databaseFileModel{file("Second", "/data/second.ndf"), file("first", "/DATA/first.mdf")}
+	actual := []databaseFileModel{file("first", "/data/first.mdf"), file("extra", "/data/extra.ndf"), file("second", "/data/second.ndf")}
+
+	got := mergeDatabaseFiles(prior, actual)
+	want := []databaseFileModel{file("Second", "/data/second.ndf"), file("first", "/DATA/first.mdf"), file("extra", "/data/extra.ndf")}
+	if len(got) != len(want) {
+		t.Fatalf("mergeDatabaseFiles() returned %d files, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Name.Equal(want[i].Name) || !got[i].FileName.Equal(want[i].FileName) {
+			t.Errorf("file %d = %s %s, want %s %s", i, got[i].Name, got[i].FileName, want[i].Name, want[i].FileName)
+		}
+	}
+
+	if got := mergeDatabaseFiles(prior, nil); len(got) != 0 {
+		t.Errorf("mergeDatabaseFiles() with no files on the server returned %d files", len(got))
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// databaseQueryStoreModel maps the query_store block of mssql_database.
+type databaseQueryStoreModel struct {
+	OperationMode           types.String `tfsdk:"operation_mode"`
+	CaptureMode             types.String `tfsdk:"capture_mode"`
+	MaxStorageSizeMb        types.Int64  `tfsdk:"max_storage_size_mb"`
+	StaleQueryThresholdDays types.Int64  `tfsdk:"stale_query_threshold_days"`
+	IntervalLengthMinutes   types.Int64  `tfsdk:"interval_length_minutes"`
+	WaitStatsCapture        types.Bool   `tfsdk:"wait_stats_capture"`
+	ActualState             types.String `tfsdk:"actual_state"`
+}
+
+func databaseQueryStoreBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Query Store settings. Removing the block leaves Query Store as it is.",
+		Attributes: map[string]schema.Attribute{
+			"operation_mode": schema.StringAttribute{
+				MarkdownDescription: "Requested operation mode: `READ_WRITE`, `READ_ONLY` or `OFF`. Defaults to `READ_WRITE`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("READ_WRITE"),
+			},
+			"capture_mode": schema.StringAttribute{
+				MarkdownDescription: "Which queries are captured: `ALL`, `AUTO`, `NONE` or `CUSTOM`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_storage_size_mb": schema.Int64Attribute{
+				MarkdownDescription: "Space reserved for Query Store in MB.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"stale_query_threshold_days": schema.Int64Attribute{
+				MarkdownDescription: "Days query information is retained (`CLEANUP_POLICY`).",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"interval_length_minutes": schema.Int64Attribute{
+				MarkdownDescription: "Runtime statistics aggregation interval in minutes: 1, 5, 10, 15, 30, 60 or 1440.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"wait_stats_capture": schema.BoolAttribute{
+				MarkdownDescription: "Whether wait statistics are captured per query (`WAIT_STATS_CAPTURE_MODE`). " +
+					"Requires SQL Server 2017 or later, and is null on earlier versions.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"actual_state": schema.StringAttribute{
+				MarkdownDescription: "Operation mode Query Store is actually in. It differs from `operation_mode` when " +
+					"SQL Server switched Query Store to `READ_ONLY`, for example because it ran out of space.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// validateQueryStore checks the values emitted as keywords.
+func validateQueryStore(diags *diag.Diagnostics, p path.Path, qs *databaseQueryStoreModel) {
+	if qs == nil {
+		return
+	}
+	validateOneOf(diags, p.AtName("operation_mode"), qs.OperationMode, "READ_WRITE", "READ_ONLY", "OFF")
+	validateOneOf(diags, p.AtName("capture_mode"), qs.CaptureMode, "ALL", "AUTO", "NONE", "CUSTOM")
+	if known(qs.IntervalLengthMinutes) {
+		switch qs.IntervalLengthMinutes.ValueInt64() {
+		case 1, 5, 10, 15, 30, 60, 1440:
+		default:
+			diags.AddAttributeError(p.AtName("interval_length_minutes"), "Invalid interval_length_minutes",
+				"interval_length_minutes must be one of 1, 5, 10, 15, 30, 60 or 1440.")
+		}
+	}
+}
+
+// queryStoreStatement returns the SET clause that moves Query Store from
+// state to plan, or "" when nothing changed. state is nil when the block was
+// not managed before.
+func queryStoreStatement(plan, state *databaseQueryStoreModel) (string, error) {
+	var current databaseQueryStoreModel
+	if state != nil {
+		current = *state
+	}
+	changed := func(planned, actual attr.Value) bool {
+		return known(planned) && !planned.Equal(actual)
+	}
+	if !changed(plan.OperationMode, current.OperationMode) && !changed(plan.CaptureMode, current.CaptureMode) &&
+		!changed(plan.MaxStorageSizeMb, current.MaxStorageSizeMb) && !changed(plan.StaleQueryThresholdDays, current.StaleQueryThresholdDays) &&
+		!changed(plan.IntervalLengthMinutes, current.IntervalLengthMinutes) && !changed(plan.WaitStatsCapture, current.WaitStatsCapture) {
+		return "", nil
+	}
+
+	for what, value := range map[string]types.String{"operation mode": plan.OperationMode, "capture mode": plan.CaptureMode} {
+		if known(value) {
+			if err := validateKeyword(what, value.ValueString()); err != nil {
+				return "", err
+			}
+		}
+	}
+	if strings.EqualFold(plan.OperationMode.ValueString(), "OFF") {
+		return "QUERY_STORE = OFF", nil
+	}
+
+	options := []string{"OPERATION_MODE = " + strings.ToUpper(plan.OperationMode.ValueString())}
+	if known(plan.CaptureMode) {
+		options = append(options, "QUERY_CAPTURE_MODE = "+strings.ToUpper(plan.CaptureMode.ValueString()))
+	}
+	if known(plan.MaxStorageSizeMb) {
+		options = append(options, fmt.Sprintf("MAX_STORAGE_SIZE_MB = %d", plan.MaxStorageSizeMb.ValueInt64()))
+	}
+	if known(plan.StaleQueryThresholdDays) {
+		options = append(options, fmt.Sprintf("CLEANUP_POLICY = (STALE_QUERY_THRESHOLD_DAYS = %d)", plan.StaleQueryThresholdDays.ValueInt64()))
+	}
+	if known(plan.IntervalLengthMinutes) {
+		options = append(options, fmt.Sprintf("INTERVAL_LENGTH_MINUTES = %d", plan.IntervalLengthMinutes.ValueInt64()))
+	}
+	if known(plan.WaitStatsCapture) {
+		options = append(options, "WAIT_STATS_CAPTURE_MODE = "+onOff(plan.WaitStatsCapture.ValueBool()))
+	}
+	return "QUERY_STORE = ON (" + strings.Join(options, ", ") + ")", nil
+}
+
+// readQueryStore returns the Query Store settings from
+// sys.database_query_store_options. db must be connected to the database.
+func readQueryStore(ctx context.Context, db *sql.DB, prior *databaseQueryStoreModel) (*databaseQueryStoreModel, error) {
+	var (
+		desiredState, actualState, captureMode string
+		maxStorageSizeMb, staleQueryThreshold  int64
+		intervalLength                         int64
+		waitStatsCapture                       sql.NullString
+		hasWaitStats                           bool
+	)
+	// Wait statistics capture was added in SQL Server 2017.
+	err := db.QueryRowContext(ctx, `
+		SELECT CAST(CASE WHEN COL_LENGTH('sys.database_query_store_options', 'wait_stats_capture_mode_desc') IS NULL
+			THEN 0 ELSE 1 END AS bit)`).Scan(&hasWaitStats)
+	if err != nil {
+		return nil, err
+	}
+	waitStatsColumn := "CAST(NULL AS nvarchar(60))"
+	if hasWaitStats {
+		waitStatsColumn = "wait_stats_capture_mode_desc"
+	}
+	err = db.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT desired_state_desc, actual_state_desc, query_capture_mode_desc,
+			max_storage_size_mb, stale_query_threshold_days, interval_length_minutes,
+			%s
+		FROM sys.database_query_store_options`, waitStatsColumn)).Scan(&desiredState, &actualState, &captureMode,
+		&maxStorageSizeMb, &staleQueryThreshold, &intervalLength, &waitStatsCapture)
+	if err != nil {
+		return nil, err
+	}
+
+	qs := &databaseQueryStoreModel{
+		OperationMode:           keepCase(prior.OperationMode, desiredState),
+		CaptureMode:             keepCase(prior.CaptureMode, captureMode),
+		MaxStorageSizeMb:        types.Int64Value(maxStorageSizeMb),
+		StaleQueryThresholdDays: types.Int64Value(staleQueryThreshold),
+		IntervalLengthMinutes:   types.Int64Value(intervalLength),
+		WaitStatsCapture:        types.BoolNull(),
+		ActualState:             types.StringValue(actualState),
+	}
+	if waitStatsCapture.Valid {
+		qs.WaitStatsCapture = types.BoolValue(waitStatsCapture.String == "ON")
+	}
+	return qs, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestQueryStoreStatement(t *testing.T) {
+	current := &databaseQueryStoreModel{
+		OperationMode:           types.StringValue("READ_WRITE"),
+		CaptureMode:             types.StringValue("AUTO"),
+		MaxStorageSizeMb:        types.Int64Value(1000),
+		StaleQueryThresholdDays: types.Int64Value(30),
+		IntervalLengthMinutes:   types.Int64Value(60),
+		WaitStatsCapture:        types.BoolValue(true),
+	}
+	cases := map[string]struct {
+		plan  databaseQueryStoreModel
+		state *databaseQueryStoreModel
+		want  string
+	}{
+		"enable with defaults": {
+			plan: databaseQueryStoreModel{
+				OperationMode:           types.StringValue("READ_WRITE"),
+				CaptureMode:             types.StringUnknown(),
+				MaxStorageSizeMb:        types.Int64Unknown(),
+				StaleQueryThresholdDays: types.Int64Unknown(),
+				IntervalLengthMinutes:   types.Int64Unknown(),
+				WaitStatsCapture:        types.BoolUnknown(),
+			},
+			want: "QUERY_STORE = ON (OPERATION_MODE = READ_WRITE)",
+		},
+		"unchanged": {
+			plan:  *current,
+			state: current,
+		},
+		"resize": {
+			plan: databaseQueryStoreModel{
+				OperationMode:           types.StringValue("read_write"),
+				CaptureMode:             types.StringValue("AUTO"),
+				MaxStorageSizeMb:        types.Int64Value(2048),
+				StaleQueryThresholdDays: types.Int64Value(30),
+				IntervalLengthMinutes:   types.Int64Value(60),
+				WaitStatsCapture:        types.BoolValue(false),
+			},
+			state: current,
+			want: "QUERY_STORE = ON (OPERATION_MODE = READ_WRITE, QUERY_CAPTURE_MODE = AUTO, MAX_STORAGE_SIZE_MB = 2048, " +
+				"CLEANUP_POLICY = (STALE_QUERY_THRESHOLD_DAYS = 30), INTERVAL_LENGTH_MINUTES = 60, WAIT_STATS_CAPTURE_MODE = OFF)",
+		},
+		"turn off": {
+			plan:  databaseQueryStoreModel{OperationMode: types.StringValue("OFF")},
+			state: current,
+			want:  "QUERY_STORE = OFF",
+		},
+	}
+	for name, tc := range cases {
+		got, err := queryStoreStatement(&tc.plan, tc.state)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: queryStoreStatement() = %q, want %q", name, got, tc.want)
+		}
+	}
+
+	_, err := queryStoreStatement(&databaseQueryStoreModel{OperationMode: types.StringValue("READ_WRITE); DROP DATABASE x; --")}, nil)
+	if err == nil {
+		t.Error("queryStoreStatement() accepted an invalid operation mode")
+	}
+}
@@ -7,10 +7,16 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -20,8 +26,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &databaseResource{}
-	_ resource.ResourceWithConfigure = &databaseResource{}
+	_ resource.Resource                   = &databaseResource{}
+	_ resource.ResourceWithConfigure      = &databaseResource{}
+	_ resource.ResourceWithModifyPlan     = &databaseResource{}
+	_ resource.ResourceWithImportState    = &databaseResource{}
+	_ resource.ResourceWithValidateConfig = &databaseResource{}
 )
 
 // NewDatabaseResource a helper function to simplify the provider implementation.
@@ -31,15 +40,38 @@ func NewDatabaseResource() resource.Resource {
 
 // maps to resource schema table
 type databaseResourceModel struct {
-	Name               types.String `tfsdk:"name"`
-	Collation          types.String `tfsdk:"collation"`
-	CompatibilityLevel types.Int32  `tfsdk:"compatibility_level"`
-	Id                 types.String `tfsdk:"id"`
+	Name                   types.String                 `tfsdk:"name"`
+	Collation              types.String                 `tfsdk:"collation"`
+	CompatibilityLevel     types.Int32                  `tfsdk:"compatibility_level"`
+	RecoveryModel          types.String                 `tfsdk:"recovery_model"`
+	ReadCommittedSnapshot  types.Bool                   `tfsdk:"read_committed_snapshot"`
+	AllowSnapshotIsolation types.Bool                   `tfsdk:"allow_snapshot_isolation"`
+	AutoClose              types.Bool                   `tfsdk:"auto_close"`
+	AutoShrink             types.Bool                   `tfsdk:"auto_shrink"`
+	PageVerify             types.String                 `tfsdk:"page_verify"`
+	Trustworthy            types.Bool                   `tfsdk:"trustworthy"`
+	Containment            types.String                 `tfsdk:"containment"`
+	ReadOnly               types.Bool                   `tfsdk:"read_only"`
+	Owner                  types.String                 `tfsdk:"owner"`
+	Edition                types.String                 `tfsdk:"edition"`
+	ServiceObjective       types.String                 `tfsdk:"service_objective"`
+	MaxSizeGb              types.Int64                  `tfsdk:"max_size_gb"`
+	ElasticPool            types.String                 `tfsdk:"elastic_pool"`
+	DeletionProtection     types.Bool                   `tfsdk:"deletion_protection"`
+	ForceDestroy           types.Bool                   `tfsdk:"force_destroy"`
+	FinalBackupPath        types.String                 `tfsdk:"final_backup_path"`
+	RestoreFrom            *databaseRestoreModel        `tfsdk:"restore_from"`
+	QueryStore             *databaseQueryStoreModel     `tfsdk:"query_store"`
+	ChangeTracking         *databaseChangeTrackingModel `tfsdk:"change_tracking"`
+	ChangeDataCapture      types.Bool                   `tfsdk:"change_data_capture"`
+	DataFiles              []databaseFileModel          `tfsdk:"data_file"`
+	LogFiles               []databaseFileModel          `tfsdk:"log_file"`
+	Id                     types.String                 `tfsdk:"id"`
 }
 
 // databaseResource is the resource implementation.
 type databaseResource struct {
-	client *sql.DB
+	client *mssqlClient
 }
 
 // Metadata returns the resource type name.
@@ -68,6 +100,142 @@ func (r *databaseResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Computed:            true,
 				Default:             int32default.StaticInt32(150),
 			},
+			"recovery_model": schema.StringAttribute{
+				MarkdownDescription: "Recovery model: `FULL`, `SIMPLE` or `BULK_LOGGED`. Defaults to the recovery model of the model database.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"read_committed_snapshot": schema.BoolAttribute{
+				MarkdownDescription: "Whether READ COMMITTED uses row versioning (`READ_COMMITTED_SNAPSHOT`). Changing it waits for other sessions in the database to finish.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"allow_snapshot_isolation": schema.BoolAttribute{
+				MarkdownDescription: "Whether transactions may use the SNAPSHOT isolation level (`ALLOW_SNAPSHOT_ISOLATION`).",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"auto_close": schema.BoolAttribute{
+				MarkdownDescription: "Whether the database is shut down after the last user disconnects (`AUTO_CLOSE`).",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"auto_shrink": schema.BoolAttribute{
+				MarkdownDescription: "Whether database files are shrunk periodically (`AUTO_SHRINK`).",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"page_verify": schema.StringAttribute{
+				MarkdownDescription: "Page verification: `CHECKSUM`, `TORN_PAGE_DETECTION` or `NONE`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"trustworthy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the server trusts the database and its contents (`TRUSTWORTHY`).",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"containment": schema.StringAttribute{
+				MarkdownDescription: "Containment: `NONE` or `PARTIAL`. `PARTIAL` requires the `contained database authentication` server option.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether the database is read-only (`READ_ONLY`).",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "Login that owns the database. Defaults to the login the provider connects as.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"change_data_capture": schema.BoolAttribute{
+				MarkdownDescription: "Whether change data capture is enabled for the database (`sys.sp_cdc_enable_db`). Tables are added with `mssql_cdc_table`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"edition": schema.StringAttribute{
+				MarkdownDescription: "Azure SQL Database edition, such as `GeneralPurpose`, `BusinessCritical`, `Hyperscale` or `Standard`. Only supported on Azure SQL Database.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"service_objective": schema.StringAttribute{
+				MarkdownDescription: "Azure SQL Database service objective, such as `GP_Gen5_2` or `S0`. Reads as `ElasticPool` for databases in an elastic pool. Setting it on a pooled database moves the database out of the pool. Only supported on Azure SQL Database.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_size_gb": schema.Int64Attribute{
+				MarkdownDescription: "Maximum size of the database in GB (`MAXSIZE`). Only supported on Azure SQL Database.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"elastic_pool": schema.StringAttribute{
+				MarkdownDescription: "Name of the elastic pool the database belongs to. Conflicts with `service_objective`. Only supported on Azure SQL Database.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether Terraform is prevented from dropping the database. Must be set to `false` and applied before the database can be destroyed.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroy disconnects other sessions with `SET SINGLE_USER WITH ROLLBACK IMMEDIATE` before dropping the database. Ignored on Azure SQL Database.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"final_backup_path": schema.StringAttribute{
+				MarkdownDescription: "Path on the server of a `COPY_ONLY` backup taken before the database is dropped. Not supported on Azure SQL Database.",
+				Optional:            true,
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Database identifier.",
@@ -76,6 +244,15 @@ func (r *databaseResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"data_file": databaseFileBlock("Data files of the `PRIMARY` filegroup. When set, the list is authoritative: " +
+				"files that are not listed are emptied into the remaining files and removed. Files of other filegroups " +
+				"are managed with `mssql_filegroup`."),
+			"log_file":        databaseFileBlock("Transaction log files. When set, the list is authoritative."),
+			"restore_from":    databaseRestoreBlock(),
+			"query_store":     databaseQueryStoreBlock(),
+			"change_tracking": databaseChangeTrackingBlock(),
+		},
 	}
 }
 
@@ -87,26 +264,119 @@ func (r *databaseResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
 	// Create database in MSSQL
 	name := data.Name.ValueString()
-	collation := data.Collation.ValueString()
-	compatibilityLevel := data.CompatibilityLevel.ValueInt32()
 
-	// Create Statement
-	createStmt := fmt.Sprintf(`
-							CREATE DATABASE %s 
-							COLLATE %s
-							WITH COMPATIBILITY_LEVEL=%d ;
-	`, name, collation, compatibilityLevel)
-	_, err := r.client.ExecContext(ctx, createStmt)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating database", err.Error())
+	if data.RestoreFrom != nil {
+		restoreStmt, diags := restoreStatement(ctx, db, name, data.RestoreFrom)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		r.client.Release(name)
+		if _, err := db.ExecContext(ctx, restoreStmt); err != nil {
+			resp.Diagnostics.AddError("Error restoring database", err.Error())
+			return
+		}
+	} else if !r.create(ctx, db, &data, &resp.Diagnostics) {
+		return
+	}
+
+	// Start from the settings the server picked, then apply the ones the
+	// configuration asks for.
+	created := databaseResourceModel{
+		Name:               data.Name,
+		DeletionProtection: data.DeletionProtection,
+		ForceDestroy:       data.ForceDestroy,
+		FinalBackupPath:    data.FinalBackupPath,
+		RestoreFrom:        data.RestoreFrom,
+		DataFiles:          []databaseFileModel{},
+		LogFiles:           []databaseFileModel{},
+		Id:                 types.StringValue(name),
+	}
+	if _, err := r.read(ctx, db, &created); err != nil {
+		resp.Diagnostics.AddError("Error reading database", err.Error())
+		resp.Diagnostics.Append(resp.State.Set(ctx, &created)...)
+		return
+	}
+	if data.RestoreFrom != nil && !data.RestoreFrom.Recovery.ValueBool() {
+		// A database left restoring accepts no ALTER DATABASE until it is
+		// recovered, so record it as the server reports it.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &created)...)
 		return
 	}
+	resp.Diagnostics.Append(r.alter(ctx, db, &data, &created)...)
+	if resp.Diagnostics.HasError() {
+		// Keep the database in state so it is tainted rather than leaked.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &created)...)
+		return
+	}
+
+	// Fill in the settings that were left to the server.
 	data.Id = types.StringValue(data.Name.ValueString())
+	if _, err := r.read(ctx, db, &data); err != nil {
+		resp.Diagnostics.AddError("Error reading database", err.Error())
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
 }
 
+// create runs CREATE DATABASE for data. It reports whether the database was
+// created.
+func (r *databaseResource) create(ctx context.Context, db *sql.DB, data *databaseResourceModel, diags *diag.Diagnostics) bool {
+	name := data.Name.ValueString()
+	collation := data.Collation.ValueString()
+
+	if err := validateKeyword("collation", collation); err != nil {
+		diags.AddAttributeError(path.Root("collation"), "Invalid collation", err.Error())
+		return false
+	}
+
+	// Create Statement. The remaining settings are not part of the
+	// CREATE DATABASE syntax and are applied with ALTER DATABASE by Create.
+	var files string
+	if len(data.DataFiles) > 0 {
+		files += " ON PRIMARY " + fileSpecs(data.DataFiles)
+	}
+	if len(data.LogFiles) > 0 {
+		files += " LOG ON " + fileSpecs(data.LogFiles)
+	}
+	options, err := serviceOptions(data, nil)
+	if err != nil {
+		diags.AddError("Invalid service objective", err.Error())
+		return false
+	}
+	var service string
+	if len(options) > 0 {
+		edition, err := r.client.EngineEdition(ctx)
+		if err != nil {
+			diags.AddError("Unable to connect to SQL Server", err.Error())
+			return false
+		}
+		if edition != engineEditionAzureSQLDatabase {
+			diags.AddError("Unsupported database settings",
+				"edition, service_objective, max_size_gb and elastic_pool are only supported on Azure SQL Database.")
+			return false
+		}
+		service = " (" + strings.Join(options, ", ") + ")"
+	}
+	createStmt := fmt.Sprintf(`
+							CREATE DATABASE %s%s
+							COLLATE %s%s ;
+	`, quoteIdentifier(name), files, collation, service)
+	_, err = db.ExecContext(ctx, createStmt)
+	if err != nil {
+		diags.AddError("Error creating database", err.Error())
+		return false
+	}
+	return true
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (r *databaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state databaseResourceModel
@@ -116,19 +386,160 @@ func (r *databaseResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	row := r.client.QueryRowContext(ctx, "SELECT name FROM sys.databases where name=@db", sql.Named("db", state.Name.ValueString()))
-	var name string
-	err := row.Scan(&name)
-	if err == sql.ErrNoRows {
-		resp.State.RemoveResource(ctx)
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
 		return
-	} else if err != nil {
+	}
+
+	found, err := r.read(ctx, db, &state)
+	if err != nil {
 		resp.Diagnostics.AddError("Error reading database", err.Error())
 		return
 	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// read populates data with the current settings of the database named in
+// data from sys.databases. It reports false if the database does not exist.
+func (r *databaseResource) read(ctx context.Context, db *sql.DB, data *databaseResourceModel) (bool, error) {
+	row := db.QueryRowContext(ctx, `
+		SELECT name, collation_name, compatibility_level,
+			recovery_model_desc, is_read_committed_snapshot_on,
+			CAST(CASE WHEN snapshot_isolation_state IN (1, 3) THEN 1 ELSE 0 END AS bit),
+			is_auto_close_on, is_auto_shrink_on, page_verify_option_desc,
+			is_trustworthy_on, containment_desc, is_read_only,
+			SUSER_SNAME(owner_sid), is_cdc_enabled
+		FROM sys.databases
+		WHERE name = @db`, sql.Named("db", data.Name.ValueString()))
+
+	var (
+		name                   string
+		collation              sql.NullString
+		compatibilityLevel     int32
+		recoveryModel          string
+		readCommittedSnapshot  bool
+		allowSnapshotIsolation bool
+		autoClose              bool
+		autoShrink             bool
+		pageVerify             string
+		trustworthy            bool
+		containment            string
+		readOnly               bool
+		owner                  sql.NullString
+		cdcEnabled             bool
+	)
+	err := row.Scan(&name, &collation, &compatibilityLevel,
+		&recoveryModel, &readCommittedSnapshot,
+		&allowSnapshotIsolation,
+		&autoClose, &autoShrink, &pageVerify,
+		&trustworthy, &containment, &readOnly,
+		&owner, &cdcEnabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	data.Name = keepCase(data.Name, name)
+	// collation_name is NULL while the database is offline or being
+	// recovered, keep the last known value in that case.
+	if collation.Valid {
+		data.Collation = keepCase(data.Collation, collation.String)
+	}
+	data.CompatibilityLevel = types.Int32Value(compatibilityLevel)
+	data.RecoveryModel = keepCase(data.RecoveryModel, recoveryModel)
+	data.ReadCommittedSnapshot = types.BoolValue(readCommittedSnapshot)
+	data.AllowSnapshotIsolation = types.BoolValue(allowSnapshotIsolation)
+	data.AutoClose = types.BoolValue(autoClose)
+	data.AutoShrink = types.BoolValue(autoShrink)
+	data.PageVerify = keepCase(data.PageVerify, pageVerify)
+	data.Trustworthy = types.BoolValue(trustworthy)
+	data.Containment = keepCase(data.Containment, containment)
+	data.ReadOnly = types.BoolValue(readOnly)
+	data.ChangeDataCapture = types.BoolValue(cdcEnabled)
+	// The owner has no name when its login was dropped.
+	prior := data.Owner
+	data.Owner = types.StringNull()
+	if owner.Valid {
+		data.Owner = keepCase(prior, owner.String)
+	}
+
+	// Destroy settings only live in state. Imported databases start out with
+	// the schema defaults.
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
+
+	edition, err := r.client.EngineEdition(ctx)
+	if err != nil {
+		return false, err
+	}
+	if edition == engineEditionAzureSQLDatabase {
+		if err := readServiceObjective(ctx, db, data); err != nil {
+			return false, err
+		}
+	} else {
+		data.Edition = types.StringNull()
+		data.ServiceObjective = types.StringNull()
+		data.MaxSizeGb = types.Int64Null()
+		data.ElasticPool = types.StringNull()
+	}
+
+	// Files are only tracked once the configuration manages them.
+	if data.DataFiles == nil {
+		data.DataFiles = []databaseFileModel{}
+	}
+	if data.LogFiles == nil {
+		data.LogFiles = []databaseFileModel{}
+	}
+	if data.ChangeTracking != nil {
+		if data.ChangeTracking, err = readChangeTracking(ctx, db, name, data.ChangeTracking); err != nil {
+			return false, err
+		}
+	}
+
+	if data.QueryStore != nil {
+		dbConn, err := r.client.Database(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		if data.QueryStore, err = readQueryStore(ctx, dbConn, data.QueryStore); err != nil {
+			return false, err
+		}
+	}
+
+	if len(data.DataFiles) > 0 || len(data.LogFiles) > 0 {
+		dbConn, err := r.client.Database(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		if len(data.DataFiles) > 0 {
+			files, err := readDatabaseFiles(ctx, dbConn, databaseFileTypeRows, primaryDataSpaceId)
+			if err != nil {
+				return false, err
+			}
+			data.DataFiles = mergeDatabaseFiles(data.DataFiles, files)
+		}
+		if len(data.LogFiles) > 0 {
+			// Log files do not belong to a filegroup.
+			files, err := readDatabaseFiles(ctx, dbConn, databaseFileTypeLog, 0)
+			if err != nil {
+				return false, err
+			}
+			data.LogFiles = mergeDatabaseFiles(data.LogFiles, files)
+		}
+	}
+	return true, nil
+}
+
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *databaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan databaseResourceModel
@@ -138,17 +549,381 @@ func (r *databaseResource) Update(ctx context.Context, req resource.UpdateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(r.alter(ctx, db, &plan, &state)...)
+	if resp.Diagnostics.HasError() {
+		// Record the changes that were applied before the failure.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	// Fill in the settings that were left to the server.
+	if _, err := r.read(ctx, db, &plan); err != nil {
+		resp.Diagnostics.AddError("Error reading database", err.Error())
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+
+}
+
+// ValidateConfig checks option values that are emitted as T-SQL keywords.
+func (r *databaseResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data databaseResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateOneOf(&resp.Diagnostics, path.Root("recovery_model"), data.RecoveryModel, "FULL", "SIMPLE", "BULK_LOGGED")
+	validateOneOf(&resp.Diagnostics, path.Root("page_verify"), data.PageVerify, "CHECKSUM", "TORN_PAGE_DETECTION", "NONE")
+	validateOneOf(&resp.Diagnostics, path.Root("containment"), data.Containment, "NONE", "PARTIAL")
+	if !data.ElasticPool.IsNull() && !data.ServiceObjective.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("elastic_pool"), "Conflicting service objective",
+			"Only one of elastic_pool and service_objective can be set.")
+	}
+	if data.RestoreFrom != nil {
+		if data.RestoreFrom.BackupPath.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("restore_from").AtName("backup_path"), "Missing backup path",
+				"restore_from requires backup_path.")
+		}
+		if len(data.DataFiles) > 0 || len(data.LogFiles) > 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("restore_from"), "Conflicting file settings",
+				"A restored database takes its files from the backup. Use restore_from.move to place them.")
+		}
+		validateUnrecovered(&resp.Diagnostics, &data)
+	}
+	validateQueryStore(&resp.Diagnostics, path.Root("query_store"), data.QueryStore)
+	validateChangeTracking(&resp.Diagnostics, path.Root("change_tracking"), data.ChangeTracking)
+	validateDatabaseFiles(&resp.Diagnostics, path.Root("data_file"), data.DataFiles, true)
+	validateDatabaseFiles(&resp.Diagnostics, path.Root("log_file"), data.LogFiles, true)
+}
+
+// ImportState imports a database by name. Read fills in the remaining
+// attributes.
+func (r *databaseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// ModifyPlan plans the Azure SQL Database service tier and forces
+// replacement for changes SQL Server cannot apply in place.
+func (r *databaseResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	planRestore(ctx, req, resp)
+	if req.State.Raw.IsNull() || r.client == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state databaseResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planServiceObjective(ctx, req, resp, &plan, &state)
+
+	if plan.Collation.IsUnknown() || plan.Collation.Equal(state.Collation) {
+		return
+	}
+
+	// Azure SQL Database only accepts a collation at creation time.
+	edition, err := r.client.EngineEdition(ctx)
+	if err != nil {
+		// The server is unreachable during plan, let apply report it.
+		return
+	}
+	if edition == engineEditionAzureSQLDatabase {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("collation"))
+	}
+}
+
+// alter applies the differences between plan and state to an existing
+// database. state is updated as each change succeeds so callers can persist
+// partial progress when an error is returned.
+func (r *databaseResource) alter(ctx context.Context, db *sql.DB, plan, state *databaseResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
 	// If name changed, rename database
 	if plan.Name.ValueString() != state.Name.ValueString() {
-		_, err := r.client.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE [%s] MODIFY NAME = [%s]", state.Name.ValueString(), plan.Name.ValueString()))
+		r.client.Release(state.Name.ValueString())
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY NAME = %s", quoteIdentifier(state.Name.ValueString()), quoteIdentifier(plan.Name.ValueString())))
 		if err != nil {
-			resp.Diagnostics.AddError("Error renaming database", err.Error())
-			return
+			diags.AddError("Error renaming database", err.Error())
+			return diags
 		}
 		state.Name = plan.Name
 	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+	name := quoteIdentifier(plan.Name.ValueString())
+
+	if !plan.Collation.IsUnknown() && !plan.Collation.Equal(state.Collation) {
+		collation := plan.Collation.ValueString()
+		if err := validateKeyword("collation", collation); err != nil {
+			diags.AddAttributeError(path.Root("collation"), "Invalid collation", err.Error())
+			return diags
+		}
+		r.client.Release(plan.Name.ValueString())
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s COLLATE %s", name, collation))
+		if err != nil {
+			diags.AddAttributeError(path.Root("collation"), "Error changing database collation", collationErrorDetail(err))
+			return diags
+		}
+		state.Collation = plan.Collation
+	}
+
+	if !plan.CompatibilityLevel.IsUnknown() && !plan.CompatibilityLevel.Equal(state.CompatibilityLevel) {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET COMPATIBILITY_LEVEL = %d", name, plan.CompatibilityLevel.ValueInt32()))
+		if err != nil {
+			diags.AddAttributeError(path.Root("compatibility_level"), "Error changing database compatibility level", err.Error())
+			return diags
+		}
+		state.CompatibilityLevel = plan.CompatibilityLevel
+	}
+
+	if known(plan.Owner) && (state.Owner.IsNull() || !strings.EqualFold(plan.Owner.ValueString(), state.Owner.ValueString())) {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER AUTHORIZATION ON DATABASE::%s TO %s", name, quoteIdentifier(plan.Owner.ValueString())))
+		if err != nil {
+			diags.AddAttributeError(path.Root("owner"), "Error changing database owner", err.Error())
+			return diags
+		}
+		state.Owner = plan.Owner
+	}
 
+	options, err := serviceOptions(plan, state)
+	if err != nil {
+		diags.AddError("Invalid service objective", err.Error())
+		return diags
+	}
+	if len(options) > 0 {
+		if known(state.ElasticPool) && !known(plan.ElasticPool) && !known(plan.ServiceObjective) {
+			diags.AddAttributeError(path.Root("service_objective"), "Missing service objective",
+				"Set service_objective to move the database out of its elastic pool.")
+			return diags
+		}
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY (%s)", name, strings.Join(options, ", ")))
+		if err != nil {
+			diags.AddError("Error scaling database", err.Error())
+			return diags
+		}
+		if err := waitForServiceObjective(ctx, db, plan); err != nil {
+			diags.AddError("Error scaling database", err.Error())
+			return diags
+		}
+		if err := readServiceObjective(ctx, db, state); err != nil {
+			diags.AddError("Error reading database", err.Error())
+			return diags
+		}
+	}
+
+	if len(plan.DataFiles) > 0 || len(plan.LogFiles) > 0 {
+		dbConn, err := r.client.Database(ctx, plan.Name.ValueString())
+		if err != nil {
+			diags.AddError("Unable to connect to database", err.Error())
+			return diags
+		}
+		files := []struct {
+			attribute   string
+			planned     []databaseFileModel
+			current     *[]databaseFileModel
+			fileType    int
+			dataSpaceId int
+			addFiles    func(specs string) string
+		}{
+			{"data_file", plan.DataFiles, &state.DataFiles, databaseFileTypeRows, primaryDataSpaceId,
+				func(specs string) string { return "ADD FILE " + specs + " TO FILEGROUP [PRIMARY]" }},
+			{"log_file", plan.LogFiles, &state.LogFiles, databaseFileTypeLog, 0,
+				func(specs string) string { return "ADD LOG FILE " + specs }},
+		}
+		for _, f := range files {
+			if len(f.planned) == 0 {
+				continue
+			}
+			actual, err := readDatabaseFiles(ctx, dbConn, f.fileType, f.dataSpaceId)
+			if err != nil {
+				diags.AddError("Error reading database files", err.Error())
+				return diags
+			}
+			diags.Append(alterDatabaseFiles(ctx, db, dbConn, plan.Name.ValueString(), path.Root(f.attribute), f.planned, actual, f.addFiles)...)
+			if diags.HasError() {
+				return diags
+			}
+			actual, err = readDatabaseFiles(ctx, dbConn, f.fileType, f.dataSpaceId)
+			if err != nil {
+				diags.AddError("Error reading database files", err.Error())
+				return diags
+			}
+			*f.current = mergeDatabaseFiles(f.planned, actual)
+		}
+	}
+
+	if plan.QueryStore != nil {
+		clause, err := queryStoreStatement(plan.QueryStore, state.QueryStore)
+		if err != nil {
+			diags.AddAttributeError(path.Root("query_store"), "Invalid Query Store settings", err.Error())
+			return diags
+		}
+		if clause != "" {
+			_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET %s", name, clause))
+			if err != nil {
+				diags.AddAttributeError(path.Root("query_store"), "Error changing Query Store settings", err.Error())
+				return diags
+			}
+			dbConn, err := r.client.Database(ctx, plan.Name.ValueString())
+			if err != nil {
+				diags.AddError("Unable to connect to database", err.Error())
+				return diags
+			}
+			state.QueryStore, err = readQueryStore(ctx, dbConn, plan.QueryStore)
+			if err != nil {
+				diags.AddError("Error reading Query Store settings", err.Error())
+				return diags
+			}
+		}
+	}
+
+	clause, err := changeTrackingStatement(plan.ChangeTracking, state.ChangeTracking)
+	if err != nil {
+		diags.AddAttributeError(path.Root("change_tracking"), "Invalid change tracking settings", err.Error())
+		return diags
+	}
+	if clause != "" {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET %s", name, clause))
+		if err != nil {
+			diags.AddAttributeError(path.Root("change_tracking"), "Error changing change tracking settings", err.Error())
+			return diags
+		}
+		state.ChangeTracking = plan.ChangeTracking
+	}
+
+	if known(plan.ChangeDataCapture) && !plan.ChangeDataCapture.Equal(state.ChangeDataCapture) {
+		dbConn, err := r.client.Database(ctx, plan.Name.ValueString())
+		if err != nil {
+			diags.AddError("Unable to connect to database", err.Error())
+			return diags
+		}
+		procedure := "sys.sp_cdc_disable_db"
+		if plan.ChangeDataCapture.ValueBool() {
+			procedure = "sys.sp_cdc_enable_db"
+		}
+		if _, err := dbConn.ExecContext(ctx, "EXEC "+procedure); err != nil {
+			diags.AddAttributeError(path.Root("change_data_capture"), "Error changing change data capture", err.Error())
+			return diags
+		}
+		state.ChangeDataCapture = plan.ChangeDataCapture
+	}
+
+	for _, option := range databaseOptions(plan, state) {
+		if !option.changed {
+			continue
+		}
+		if option.keyword != "" {
+			if err := validateKeyword(option.attribute, option.keyword); err != nil {
+				diags.AddAttributeError(path.Root(option.attribute), "Invalid attribute value", err.Error())
+				return diags
+			}
+		}
+		if option.exclusive {
+			r.client.Release(plan.Name.ValueString())
+		}
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET %s", name, option.clause))
+		if err != nil {
+			diags.AddAttributeError(path.Root(option.attribute), "Error changing database option", err.Error())
+			return diags
+		}
+		option.commit()
+	}
+
+	return diags
+}
+
+// databaseOption is a single ALTER DATABASE ... SET clause.
+type databaseOption struct {
+	attribute string
+	changed   bool
+	clause    string
+	// keyword is the configured value for options that interpolate it
+	// into clause.
+	keyword string
+	// exclusive is set for options that wait for other sessions in the
+	// database to finish, so the provider's own pooled sessions are closed
+	// first.
+	exclusive bool
+	// commit records the new value in state once the clause succeeded.
+	commit func()
+}
+
+// databaseOptions lists the SET clauses needed to move state to plan, in the
+// order they must run. A read-only database must be made writable before
+// any other option can change, and made read-only only after all of them.
+func databaseOptions(plan, state *databaseResourceModel) []databaseOption {
+	stringOption := func(attribute string, planned types.String, current *types.String, format string) databaseOption {
+		return databaseOption{
+			attribute: attribute,
+			changed:   !planned.IsUnknown() && !planned.IsNull() && !strings.EqualFold(planned.ValueString(), current.ValueString()),
+			clause:    fmt.Sprintf(format, strings.ToUpper(planned.ValueString())),
+			keyword:   planned.ValueString(),
+			commit:    func() { *current = planned },
+		}
+	}
+	boolOption := func(attribute string, planned types.Bool, current *types.Bool, keyword string) databaseOption {
+		return databaseOption{
+			attribute: attribute,
+			changed:   !planned.IsUnknown() && !planned.IsNull() && !planned.Equal(*current),
+			clause:    keyword + " " + onOff(planned.ValueBool()),
+			commit:    func() { *current = planned },
+		}
+	}
+	readOnly := databaseOption{
+		attribute: "read_only",
+		changed:   !plan.ReadOnly.IsUnknown() && !plan.ReadOnly.IsNull() && !plan.ReadOnly.Equal(state.ReadOnly),
+		clause:    "READ_WRITE",
+		exclusive: true,
+		commit:    func() { state.ReadOnly = plan.ReadOnly },
+	}
+	if plan.ReadOnly.ValueBool() {
+		readOnly.clause = "READ_ONLY"
+	}
+
+	rcsi := boolOption("read_committed_snapshot", plan.ReadCommittedSnapshot, &state.ReadCommittedSnapshot, "READ_COMMITTED_SNAPSHOT")
+	rcsi.exclusive = true
+	options := []databaseOption{
+		stringOption("recovery_model", plan.RecoveryModel, &state.RecoveryModel, "RECOVERY %s"),
+		rcsi,
+		boolOption("allow_snapshot_isolation", plan.AllowSnapshotIsolation, &state.AllowSnapshotIsolation, "ALLOW_SNAPSHOT_ISOLATION"),
+		boolOption("auto_close", plan.AutoClose, &state.AutoClose, "AUTO_CLOSE"),
+		boolOption("auto_shrink", plan.AutoShrink, &state.AutoShrink, "AUTO_SHRINK"),
+		stringOption("page_verify", plan.PageVerify, &state.PageVerify, "PAGE_VERIFY %s"),
+		boolOption("trustworthy", plan.Trustworthy, &state.Trustworthy, "TRUSTWORTHY"),
+		stringOption("containment", plan.Containment, &state.Containment, "CONTAINMENT = %s"),
+	}
+
+	if plan.ReadOnly.ValueBool() {
+		return append(options, readOnly)
+	}
+	return append([]databaseOption{readOnly}, options...)
+}
+
+// collationErrorDetail explains the common reasons SQL Server refuses to
+// change a database collation.
+func collationErrorDetail(err error) string {
+	switch {
+	case hasSQLError(err, 5075, 5072):
+		return err.Error() + "\n\nThe collation cannot be changed while objects depend on it, such as schema-bound " +
+			"views or functions, computed columns, CHECK constraints or table-valued functions. " +
+			"Drop or alter the objects listed above and apply again."
+	case hasSQLError(err, 5030):
+		return err.Error() + "\n\nChanging the collation requires exclusive access to the database. " +
+			"Disconnect other sessions and apply again."
+	default:
+		return err.Error()
+	}
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -159,8 +934,61 @@ func (r *databaseResource) Delete(ctx context.Context, req resource.DeleteReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf("DROP DATABASE [%s]", data.Name.ValueString()))
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	name := quoteIdentifier(data.Name.ValueString())
+
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Database is protected from deletion",
+			fmt.Sprintf("Database %q has deletion_protection enabled. Set deletion_protection = false and apply "+
+				"before destroying it.", data.Name.ValueString()))
+		return
+	}
+
+	edition, err := r.client.EngineEdition(ctx)
 	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	azure := edition == engineEditionAzureSQLDatabase
+
+	// Close pooled sessions other resources opened in this database.
+	r.client.Release(data.Name.ValueString())
+
+	if backupPath := data.FinalBackupPath.ValueString(); backupPath != "" {
+		if azure {
+			resp.Diagnostics.AddAttributeError(path.Root("final_backup_path"), "Unsupported final backup",
+				"Azure SQL Database does not support BACKUP DATABASE. Remove final_backup_path and rely on the automatic backups.")
+			return
+		}
+		_, err = db.ExecContext(ctx, fmt.Sprintf("BACKUP DATABASE %s TO DISK = %s WITH COPY_ONLY, INIT", name, quoteString(backupPath)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error taking final backup", err.Error())
+			return
+		}
+	}
+
+	// Azure SQL Database drops databases with open sessions and does not
+	// support SINGLE_USER.
+	force := data.ForceDestroy.ValueBool() && !azure
+	if force {
+		_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET SINGLE_USER WITH ROLLBACK IMMEDIATE", name))
+		if err != nil {
+			resp.Diagnostics.AddError("Error disconnecting database sessions", err.Error())
+			return
+		}
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE %s", name))
+	if err != nil {
+		if force {
+			// Do not leave the database unusable for everyone else.
+			_, _ = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET MULTI_USER", name))
+		}
 		resp.Diagnostics.AddError("Error deleting database", err.Error())
 		return
 	}
@@ -171,13 +999,28 @@ func (r *databaseResource) Configure(_ context.Context, req resource.ConfigureRe
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*sql.DB)
+	client, ok := req.ProviderData.(*mssqlClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sql.DB, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 	r.client = client
 }
+
+// validateOneOf adds an attribute error when a known, non-null value is not
+// one of allowed. The comparison ignores case.
+func validateOneOf(diags *diag.Diagnostics, p path.Path, value types.String, allowed ...string) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(value.ValueString(), a) {
+			return
+		}
+	}
+	diags.AddAttributeError(p, "Invalid attribute value",
+		fmt.Sprintf("Expected one of %s, got %q.", strings.Join(allowed, ", "), value.ValueString()))
+}
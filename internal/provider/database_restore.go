@@ -0,0 +1,294 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// databaseRestoreModel maps the restore_from block of mssql_database.
+type databaseRestoreModel struct {
+	BackupPath types.String `tfsdk:"backup_path"`
+	Move       types.Map    `tfsdk:"move"`
+	Replace    types.Bool   `tfsdk:"replace"`
+	Recovery   types.Bool   `tfsdk:"recovery"`
+}
+
+// backupFile is a row of RESTORE FILELISTONLY.
+type backupFile struct {
+	logicalName  string
+	physicalName string
+	// fileType is D for data, L for log, S for FILESTREAM and F for full
+	// text catalogs.
+	fileType string
+}
+
+func databaseRestoreBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Create the database by restoring a full backup instead of running `CREATE DATABASE`. " +
+			"The remaining settings are applied once the restore completes. Changing the block replaces the database.",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"backup_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the backup file on the server.",
+				Optional:            true,
+			},
+			"move": schema.MapAttribute{
+				MarkdownDescription: "New paths for files in the backup, keyed by logical file name. Files that are not " +
+					"listed are placed in the server's default data and log directories, named after the database.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"replace": schema.BoolAttribute{
+				MarkdownDescription: "Whether to overwrite an existing database or files (`REPLACE`).",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"recovery": schema.BoolAttribute{
+				MarkdownDescription: "Whether to bring the database online after the restore (`RECOVERY`). " +
+					"With `false` the database is left restoring (`NORECOVERY`) and none of its settings can be applied.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+		},
+	}
+}
+
+// planRestore leaves the collation and compatibility level of a restored
+// database to the backup unless the configuration sets them, instead of
+// converging it to the schema defaults. Once the database exists they keep
+// the values in state.
+func planRestore(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var config databaseResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.RestoreFrom == nil {
+		return
+	}
+
+	collation := types.StringUnknown()
+	compatibilityLevel := types.Int32Unknown()
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("collation"), &collation)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("compatibility_level"), &compatibilityLevel)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if config.Collation.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("collation"), collation)...)
+	}
+	if config.CompatibilityLevel.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("compatibility_level"), compatibilityLevel)...)
+	}
+}
+
+// validateUnrecovered rejects settings on a database that is restored
+// without recovery, since it accepts no ALTER DATABASE until it is recovered.
+func validateUnrecovered(diags *diag.Diagnostics, data *databaseResourceModel) {
+	if !data.RestoreFrom.Recovery.Equal(types.BoolValue(false)) {
+		return
+	}
+	settings := []struct {
+		name  string
+		value attr.Value
+	}{
+		{"collation", data.Collation},
+		{"compatibility_level", data.CompatibilityLevel},
+		{"recovery_model", data.RecoveryModel},
+		{"read_committed_snapshot", data.ReadCommittedSnapshot},
+		{"allow_snapshot_isolation", data.AllowSnapshotIsolation},
+		{"auto_close", data.AutoClose},
+		{"auto_shrink", data.AutoShrink},
+		{"page_verify", data.PageVerify},
+		{"trustworthy", data.Trustworthy},
+		{"containment", data.Containment},
+		{"read_only", data.ReadOnly},
+		{"owner", data.Owner},
+		{"edition", data.Edition},
+		{"service_objective", data.ServiceObjective},
+		{"max_size_gb", data.MaxSizeGb},
+		{"elastic_pool", data.ElasticPool},
+		{"change_data_capture", data.ChangeDataCapture},
+	}
+	for _, s := range settings {
+		if !s.value.IsNull() {
+			diags.AddAttributeError(path.Root(s.name), "Setting requires recovery",
+				"A database restored with recovery = false accepts no settings until it is recovered.")
+		}
+	}
+	if data.QueryStore != nil {
+		diags.AddAttributeError(path.Root("query_store"), "Setting requires recovery",
+			"A database restored with recovery = false accepts no settings until it is recovered.")
+	}
+	if data.ChangeTracking != nil {
+		diags.AddAttributeError(path.Root("change_tracking"), "Setting requires recovery",
+			"A database restored with recovery = false accepts no settings until it is recovered.")
+	}
+}
+
+// restoreStatement builds the RESTORE DATABASE statement for the database
+// name. Every file in the backup is moved, either to the path given in
+// restore.Move or to the instance's default directory, so restoring a copy
+// next to the original database does not collide with its files.
+func restoreStatement(ctx context.Context, db *sql.DB, name string, restore *databaseRestoreModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	backupPath := restore.BackupPath.ValueString()
+
+	moves := map[string]string{}
+	diags.Append(restore.Move.ElementsAs(ctx, &moves, false)...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	files, err := readBackupFiles(ctx, db, backupPath)
+	if err != nil {
+		diags.AddError("Error reading backup file list", err.Error())
+		return "", diags
+	}
+
+	var dataPath, logPath string
+	err = db.QueryRowContext(ctx, `
+		SELECT CAST(SERVERPROPERTY('InstanceDefaultDataPath') AS nvarchar(4000)),
+			CAST(SERVERPROPERTY('InstanceDefaultLogPath') AS nvarchar(4000))`).Scan(&dataPath, &logPath)
+	if err != nil {
+		diags.AddError("Error reading default file locations", err.Error())
+		return "", diags
+	}
+
+	options := []string{}
+	inBackup := map[string]bool{}
+	for _, f := range files {
+		inBackup[f.logicalName] = true
+		target, ok := moves[f.logicalName]
+		if !ok {
+			dir := dataPath
+			if f.fileType == "L" {
+				dir = logPath
+			}
+			if !strings.HasSuffix(dir, "/") && !strings.HasSuffix(dir, `\`) {
+				dir += separatorOf(dir)
+			}
+			target = dir + defaultRestoreFileName(name, f)
+		}
+		options = append(options, fmt.Sprintf("MOVE %s TO %s", quoteString(f.logicalName), quoteString(target)))
+	}
+	for logical := range moves {
+		if !inBackup[logical] {
+			diags.AddError("Unknown file in restore_from.move",
+				fmt.Sprintf("The backup %q does not contain a file with the logical name %q.", backupPath, logical))
+		}
+	}
+	if diags.HasError() {
+		return "", diags
+	}
+
+	if restore.Replace.ValueBool() {
+		options = append(options, "REPLACE")
+	}
+	if restore.Recovery.IsNull() || restore.Recovery.ValueBool() {
+		options = append(options, "RECOVERY")
+	} else {
+		options = append(options, "NORECOVERY")
+	}
+
+	return fmt.Sprintf("RESTORE DATABASE %s FROM DISK = %s WITH %s",
+		quoteIdentifier(name), quoteString(backupPath), strings.Join(options, ", ")), diags
+}
+
+// readBackupFiles lists the files in a backup with RESTORE FILELISTONLY. The
+// result set has grown columns across SQL Server versions, so only the ones
+// needed are picked out by name.
+func readBackupFiles(ctx context.Context, db *sql.DB, backupPath string) ([]backupFile, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("RESTORE FILELISTONLY FROM DISK = %s", quoteString(backupPath)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, c := range columns {
+		index[c] = i
+	}
+	column := func(name string) (int, error) {
+		idx, ok := index[name]
+		if !ok {
+			return 0, fmt.Errorf("RESTORE FILELISTONLY returned no %s column", name)
+		}
+		return idx, nil
+	}
+	logicalName, err := column("LogicalName")
+	if err != nil {
+		return nil, err
+	}
+	physicalName, err := column("PhysicalName")
+	if err != nil {
+		return nil, err
+	}
+	fileType, err := column("Type")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []backupFile
+	for rows.Next() {
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		files = append(files, backupFile{
+			logicalName:  fmt.Sprint(values[logicalName]),
+			physicalName: fmt.Sprint(values[physicalName]),
+			fileType:     fmt.Sprint(values[fileType]),
+		})
+	}
+	return files, rows.Err()
+}
+
+// defaultRestoreFileName names a restored file after the target database and
+// its logical name, keeping the extension of the original file.
+func defaultRestoreFileName(database string, f backupFile) string {
+	base := f.physicalName
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+	var ext string
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		ext = base[i:]
+	}
+	return database + "_" + f.logicalName + ext
+}
+
+// separatorOf guesses the path separator of a directory on the server, which
+// may run a different operating system than the provider.
+func separatorOf(dir string) string {
+	if strings.Contains(dir, `\`) {
+		return `\`
+	}
+	return "/"
+}
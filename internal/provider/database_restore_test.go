@@ -0,0 +1,23 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "testing"
+
+func TestDefaultRestoreFileName(t *testing.T) {
+	cases := []struct {
+		file backupFile
+		want string
+	}{
+		{backupFile{logicalName: "app", physicalName: `C:\Program Files\MSSQL\DATA\app.mdf`}, "copy_app.mdf"},
+		{backupFile{logicalName: "app_log", physicalName: "/var/opt/mssql/data/app_log.ldf"}, "copy_app_log.ldf"},
+		{backupFile{logicalName: "fs", physicalName: "/var/opt/mssql/data/fs"}, "copy_fs"},
+		{backupFile{logicalName: "app", physicalName: "/data/v1.2/app"}, "copy_app"},
+	}
+	for _, tc := range cases {
+		if got := defaultRestoreFileName("copy", tc.file); got != tc.want {
+			t.Errorf("defaultRestoreFileName(%q) = %q, want %q", tc.file.physicalName, got, tc.want)
+		}
+	}
+}
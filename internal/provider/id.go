@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// idSeparator joins the parts of composite resource IDs, for example
+// "database/role" or "database/role/member".
+const idSeparator = "/"
+
+// joinId builds a composite resource ID from its parts.
+func joinId(parts ...string) string {
+	return strings.Join(parts, idSeparator)
+}
+
+// splitId parses a composite resource ID. format describes the expected
+// layout, such as "database/role", and is used for both the number of parts
+// and the error message.
+func splitId(id, format string) ([]string, error) {
+	want := strings.Count(format, idSeparator) + 1
+	parts := strings.Split(id, idSeparator)
+	if len(parts) != want {
+		return nil, fmt.Errorf("expected an ID of the form %q, got %q", format, id)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("expected an ID of the form %q with no empty parts, got %q", format, id)
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitId(t *testing.T) {
+	parts, err := splitId("appdb/readers/app_user", "database/role_name/member_name")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []string{"appdb", "readers", "app_user"}; !reflect.DeepEqual(parts, want) {
+		t.Errorf("got %q, want %q", parts, want)
+	}
+
+	for _, id := range []string{"appdb", "appdb/readers/extra", "/readers", "appdb/", ""} {
+		if _, err := splitId(id, "database/name"); err == nil {
+			t.Errorf("splitId(%q) expected an error", id)
+		}
+	}
+
+	if got := joinId("appdb", "readers"); got != "appdb/readers" {
+		t.Errorf("joinId: got %q", got)
+	}
+}
@@ -0,0 +1,385 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &cdcTableResource{}
+	_ resource.ResourceWithConfigure   = &cdcTableResource{}
+	_ resource.ResourceWithImportState = &cdcTableResource{}
+)
+
+// NewMssqlCdcTableResource a helper function to simplify the provider implementation.
+func NewMssqlCdcTableResource() resource.Resource {
+	return &cdcTableResource{}
+}
+
+// maps to resource schema table
+type cdcTableResourceModel struct {
+	Database           types.String `tfsdk:"database"`
+	Schema             types.String `tfsdk:"schema"`
+	Table              types.String `tfsdk:"table"`
+	CaptureInstance    types.String `tfsdk:"capture_instance"`
+	RoleName           types.String `tfsdk:"role_name"`
+	SupportsNetChanges types.Bool   `tfsdk:"supports_net_changes"`
+	CapturedColumns    types.List   `tfsdk:"captured_columns"`
+	Filegroup          types.String `tfsdk:"filegroup"`
+	Id                 types.String `tfsdk:"id"`
+}
+
+// cdcTableResource is the resource implementation.
+type cdcTableResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *cdcTableResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cdc_table"
+}
+
+// Schema defines the schema for the resource. Capture instances cannot be
+// altered, so every change replaces them.
+func (r *cdcTableResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Change data capture table resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database name. Change data capture must be enabled with `change_data_capture` on `mssql_database`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Schema of the source table. Defaults to `dbo`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("dbo"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table": schema.StringAttribute{
+				MarkdownDescription: "Source table name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"capture_instance": schema.StringAttribute{
+				MarkdownDescription: "Name of the capture instance. Defaults to `<schema>_<table>`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role_name": schema.StringAttribute{
+				MarkdownDescription: "Database role that gates access to the change data. When unset, access is not gated by a role.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"supports_net_changes": schema.BoolAttribute{
+				MarkdownDescription: "Whether net change queries are supported. Requires a primary key or unique index. " +
+					"Defaults to `true` when the table has a primary key.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"captured_columns": schema.ListAttribute{
+				MarkdownDescription: "Columns included in the change table. Defaults to all columns.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"filegroup": schema.StringAttribute{
+				MarkdownDescription: "Filegroup of the change table. Defaults to the default filegroup of the database.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier, in the form `database/schema/table/capture_instance`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *cdcTableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data cdcTableResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+
+	// Unset arguments are passed as NULL so the procedure picks its defaults.
+	var capturedColumns any
+	if known(data.CapturedColumns) {
+		var columns []string
+		resp.Diagnostics.Append(data.CapturedColumns.ElementsAs(ctx, &columns, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		quoted := make([]string, 0, len(columns))
+		for _, c := range columns {
+			quoted = append(quoted, quoteIdentifier(c))
+		}
+		capturedColumns = strings.Join(quoted, ",")
+	}
+	_, err = db.ExecContext(ctx, `
+		EXEC sys.sp_cdc_enable_table
+			@source_schema = @schema,
+			@source_name = @table,
+			@role_name = @role,
+			@capture_instance = @instance,
+			@supports_net_changes = @net,
+			@captured_column_list = @columns,
+			@filegroup_name = @filegroup`,
+		sql.Named("schema", data.Schema.ValueString()),
+		sql.Named("table", data.Table.ValueString()),
+		sql.Named("role", nullString(data.RoleName)),
+		sql.Named("instance", nullString(data.CaptureInstance)),
+		sql.Named("net", nullBool(data.SupportsNetChanges)),
+		sql.Named("columns", capturedColumns),
+		sql.Named("filegroup", nullString(data.Filegroup)))
+	if err != nil {
+		detail := err.Error()
+		if hasSQLError(err, 22901) {
+			detail += "\n\nEnable change data capture on the database first, with change_data_capture = true on mssql_database."
+		}
+		resp.Diagnostics.AddError("Error enabling change data capture", detail)
+		return
+	}
+
+	found, err := r.read(ctx, db, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading change data capture", err.Error())
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Error reading change data capture", "The capture instance was not found after it was created.")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *cdcTableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state cdcTableResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+
+	found, err := r.read(ctx, db, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading change data capture", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// read populates data from cdc.change_tables and cdc.captured_columns. When
+// the capture instance is not known yet, the newest one of the table is used.
+// It reports false if the capture instance does not exist.
+func (r *cdcTableResource) read(ctx context.Context, db *sql.DB, data *cdcTableResourceModel) (bool, error) {
+	var (
+		objectId           int
+		captureInstance    string
+		supportsNetChanges bool
+		roleName           sql.NullString
+		filegroup          sql.NullString
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT TOP 1 object_id, capture_instance, supports_net_changes, role_name, filegroup_name
+		FROM cdc.change_tables
+		WHERE source_object_id = OBJECT_ID(@p1) AND (@p2 IS NULL OR capture_instance = @p2)
+		ORDER BY create_date DESC`,
+		qualifiedName(data.Schema.ValueString(), data.Table.ValueString()), nullString(data.CaptureInstance)).
+		Scan(&objectId, &captureInstance, &supportsNetChanges, &roleName, &filegroup)
+	if err == sql.ErrNoRows || hasSQLError(err, 208) {
+		// 208: the cdc schema does not exist once CDC is disabled on the
+		// database.
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name FROM cdc.captured_columns WHERE object_id = @p1 ORDER BY column_ordinal`, objectId)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return false, err
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	data.CaptureInstance = keepCase(data.CaptureInstance, captureInstance)
+	data.SupportsNetChanges = types.BoolValue(supportsNetChanges)
+	data.RoleName = types.StringNull()
+	if roleName.Valid {
+		data.RoleName = keepCase(data.RoleName, roleName.String)
+	}
+	data.Filegroup = types.StringNull()
+	if filegroup.Valid {
+		data.Filegroup = keepCase(data.Filegroup, filegroup.String)
+	}
+	data.CapturedColumns = capturedColumnsValue(data.CapturedColumns, columns)
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Schema.ValueString(), data.Table.ValueString(), data.CaptureInstance.ValueString()))
+	return true, nil
+}
+
+// capturedColumnsValue keeps the configured spelling of column names that
+// match the captured columns case-insensitively.
+func capturedColumnsValue(prior types.List, actual []string) types.List {
+	values := make([]types.String, len(actual))
+	var priorElements []string
+	if known(prior) {
+		for _, e := range prior.Elements() {
+			if s, ok := e.(types.String); ok {
+				priorElements = append(priorElements, s.ValueString())
+			}
+		}
+	}
+	for i, column := range actual {
+		values[i] = types.StringValue(column)
+		if i < len(priorElements) && strings.EqualFold(priorElements[i], column) {
+			values[i] = types.StringValue(priorElements[i])
+		}
+	}
+	list, _ := types.ListValueFrom(context.Background(), types.StringType, values)
+	return list
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Every attribute forces replacement, so there is nothing to apply.
+func (r *cdcTableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan cdcTableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...) // Read plan
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *cdcTableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data cdcTableResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, `
+		EXEC sys.sp_cdc_disable_table
+			@source_schema = @schema,
+			@source_name = @table,
+			@capture_instance = @instance`,
+		sql.Named("schema", data.Schema.ValueString()),
+		sql.Named("table", data.Table.ValueString()),
+		sql.Named("instance", data.CaptureInstance.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error disabling change data capture", err.Error())
+		return
+	}
+}
+
+// ImportState imports a capture instance from an ID of the form
+// database/schema/table/capture_instance.
+func (r *cdcTableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/schema/table/capture_instance")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("schema"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("table"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("capture_instance"), parts[3])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *cdcTableResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
@@ -0,0 +1,436 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &certificateResource{}
+	_ resource.ResourceWithConfigure      = &certificateResource{}
+	_ resource.ResourceWithImportState    = &certificateResource{}
+	_ resource.ResourceWithValidateConfig = &certificateResource{}
+)
+
+// NewMssqlCertificateResource a helper function to simplify the provider implementation.
+func NewMssqlCertificateResource() resource.Resource {
+	return &certificateResource{}
+}
+
+// maps to resource schema table
+type certificateResourceModel struct {
+	Database           types.String           `tfsdk:"database"`
+	Name               types.String           `tfsdk:"name"`
+	Subject            types.String           `tfsdk:"subject"`
+	ExpiryDate         types.String           `tfsdk:"expiry_date"`
+	EncryptionPassword types.String           `tfsdk:"encryption_password"`
+	FromFile           *certificateFilesModel `tfsdk:"from_file"`
+	Backup             *certificateFilesModel `tfsdk:"backup"`
+	Thumbprint         types.String           `tfsdk:"thumbprint"`
+	Id                 types.String           `tfsdk:"id"`
+}
+
+// certificateFilesModel maps the from_file and backup blocks, which both
+// name a certificate file and an optional private key file.
+type certificateFilesModel struct {
+	CertificatePath    types.String `tfsdk:"certificate_path"`
+	PrivateKeyPath     types.String `tfsdk:"private_key_path"`
+	PrivateKeyPassword types.String `tfsdk:"private_key_password"`
+}
+
+// certificateResource is the resource implementation.
+type certificateResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *certificateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate"
+}
+
+func certificateFilesBlock(desc, passwordDesc string) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: desc,
+		Attributes: map[string]schema.Attribute{
+			"certificate_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the certificate file on the server.",
+				Optional:            true,
+			},
+			"private_key_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the private key file on the server.",
+				Optional:            true,
+			},
+			"private_key_password": schema.StringAttribute{
+				MarkdownDescription: passwordDesc,
+				Optional:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+// Schema defines the schema for the resource.
+func (r *certificateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	fromFile := certificateFilesBlock("Create the certificate from files on the server instead of generating it. "+
+		"Changing the block replaces the certificate.", "Password that decrypts the private key file.")
+	fromFile.PlanModifiers = []planmodifier.Object{
+		objectplanmodifier.RequiresReplace(),
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Certificate resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database the certificate is created in. Defaults to `master`, where certificates " +
+					"used by `mssql_database_encryption_key` must live.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("master"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Certificate name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "Subject of a generated certificate. Required unless `from_file` is set.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"expiry_date": schema.StringAttribute{
+				MarkdownDescription: "Expiry date of a generated certificate as an RFC 3339 timestamp, such as " +
+					"`2030-12-31T00:00:00Z`. Defaults to one year after creation.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"encryption_password": schema.StringAttribute{
+				MarkdownDescription: "Password that encrypts the private key in the database. When unset, the private key " +
+					"is encrypted by the database master key.",
+				Optional:  true,
+				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"thumbprint": schema.StringAttribute{
+				MarkdownDescription: "SHA-1 hash of the certificate, as a `0x` prefixed hexadecimal string.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Certificate identifier, in the form `database/name`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"from_file": fromFile,
+			"backup": certificateFilesBlock("Back up the certificate to files on the server (`BACKUP CERTIFICATE`). "+
+				"The backup is taken on create and whenever the block changes. SQL Server does not overwrite existing files.",
+				"Password that encrypts the private key file. Required with `private_key_path`."),
+		},
+	}
+}
+
+func (r *certificateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data certificateResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.FromFile != nil {
+		if data.FromFile.CertificatePath.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("from_file").AtName("certificate_path"), "Missing certificate path",
+				"from_file requires certificate_path.")
+		}
+		for name, v := range map[string]types.String{"subject": data.Subject, "expiry_date": data.ExpiryDate} {
+			if !v.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(name), "Conflicting certificate settings",
+					name+" is read from the certificate file when from_file is set.")
+			}
+		}
+	} else if data.Subject.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("subject"), "Missing subject",
+			"subject is required unless the certificate is created from_file.")
+	}
+	if known(data.ExpiryDate) {
+		if _, err := time.Parse(time.RFC3339, data.ExpiryDate.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("expiry_date"), "Invalid expiry_date",
+				"expiry_date must be an RFC 3339 timestamp, such as 2030-12-31T00:00:00Z.")
+		}
+	}
+	if data.Backup != nil {
+		if data.Backup.CertificatePath.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("backup").AtName("certificate_path"), "Missing certificate path",
+				"backup requires certificate_path.")
+		}
+		if !data.Backup.PrivateKeyPath.IsNull() && data.Backup.PrivateKeyPassword.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("backup").AtName("private_key_password"), "Missing private key password",
+				"A private key backup must be encrypted with private_key_password.")
+		}
+	}
+}
+
+// createCertificateStatement builds the CREATE CERTIFICATE statement for the
+// planned certificate.
+func createCertificateStatement(data *certificateResourceModel) string {
+	name := quoteIdentifier(data.Name.ValueString())
+	if f := data.FromFile; f != nil {
+		stmt := fmt.Sprintf("CREATE CERTIFICATE %s FROM FILE = %s", name, quoteString(f.CertificatePath.ValueString()))
+		if known(f.PrivateKeyPath) {
+			key := []string{"FILE = " + quoteString(f.PrivateKeyPath.ValueString())}
+			if known(f.PrivateKeyPassword) {
+				key = append(key, "DECRYPTION BY PASSWORD = "+quoteString(f.PrivateKeyPassword.ValueString()))
+			}
+			if known(data.EncryptionPassword) {
+				key = append(key, "ENCRYPTION BY PASSWORD = "+quoteString(data.EncryptionPassword.ValueString()))
+			}
+			stmt += " WITH PRIVATE KEY (" + strings.Join(key, ", ") + ")"
+		}
+		return stmt
+	}
+
+	stmt := "CREATE CERTIFICATE " + name
+	if known(data.EncryptionPassword) {
+		stmt += " ENCRYPTION BY PASSWORD = " + quoteString(data.EncryptionPassword.ValueString())
+	}
+	stmt += " WITH SUBJECT = " + quoteString(data.Subject.ValueString())
+	if known(data.ExpiryDate) {
+		// CREATE CERTIFICATE takes the expiry date in UTC.
+		expiry, _ := time.Parse(time.RFC3339, data.ExpiryDate.ValueString())
+		stmt += ", EXPIRY_DATE = " + quoteString(expiry.UTC().Format("2006-01-02T15:04:05"))
+	}
+	return stmt
+}
+
+// backupCertificateStatement builds the BACKUP CERTIFICATE statement for the
+// backup block.
+func backupCertificateStatement(data *certificateResourceModel) string {
+	b := data.Backup
+	stmt := fmt.Sprintf("BACKUP CERTIFICATE %s TO FILE = %s",
+		quoteIdentifier(data.Name.ValueString()), quoteString(b.CertificatePath.ValueString()))
+	if known(b.PrivateKeyPath) {
+		key := []string{
+			"FILE = " + quoteString(b.PrivateKeyPath.ValueString()),
+			"ENCRYPTION BY PASSWORD = " + quoteString(b.PrivateKeyPassword.ValueString()),
+		}
+		if known(data.EncryptionPassword) {
+			key = append(key, "DECRYPTION BY PASSWORD = "+quoteString(data.EncryptionPassword.ValueString()))
+		}
+		stmt += " WITH PRIVATE KEY (" + strings.Join(key, ", ") + ")"
+	}
+	return stmt
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *certificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data certificateResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	if _, err := db.ExecContext(ctx, createCertificateStatement(&data)); err != nil {
+		resp.Diagnostics.AddError("Error creating certificate", err.Error())
+		return
+	}
+
+	found, err := r.read(ctx, db, &data)
+	if err != nil || !found {
+		if err == nil {
+			err = fmt.Errorf("certificate %q was not found after it was created", data.Name.ValueString())
+		}
+		resp.Diagnostics.AddError("Error reading certificate", err.Error())
+		return
+	}
+	// Save the certificate before backing it up, so a failed backup does not
+	// leave it unmanaged.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Backup == nil {
+		return
+	}
+
+	if _, err := db.ExecContext(ctx, backupCertificateStatement(&data)); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("backup"), "Error backing up certificate", err.Error())
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("backup"), (*certificateFilesModel)(nil))...)
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *certificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state certificateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	found, err := r.read(ctx, db, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading certificate", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// read populates data from sys.certificates. It reports false if the
+// certificate does not exist.
+func (r *certificateResource) read(ctx context.Context, db *sql.DB, data *certificateResourceModel) (bool, error) {
+	var (
+		subject, thumbprint string
+		expiry              time.Time
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT subject, expiry_date, CONVERT(varchar(64), thumbprint, 1)
+		FROM sys.certificates WHERE name = @p1`, data.Name.ValueString()).Scan(&subject, &expiry, &thumbprint)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	data.Subject = types.StringValue(subject)
+	data.ExpiryDate = certificateDate(data.ExpiryDate, expiry)
+	data.Thumbprint = types.StringValue(thumbprint)
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Name.ValueString()))
+	return true, nil
+}
+
+// certificateDate formats a UTC date from sys.certificates, keeping the prior
+// value when it denotes the same instant in another offset.
+func certificateDate(prior types.String, actual time.Time) types.String {
+	// The driver returns datetime values without a zone; the catalog stores
+	// them in UTC.
+	actual = time.Date(actual.Year(), actual.Month(), actual.Day(), actual.Hour(), actual.Minute(), actual.Second(), 0, time.UTC)
+	if known(prior) {
+		if t, err := time.Parse(time.RFC3339, prior.ValueString()); err == nil && t.Equal(actual) {
+			return prior
+		}
+	}
+	return types.StringValue(actual.Format(time.RFC3339))
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Only the backup block can change in place.
+func (r *certificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state certificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...) // Read plan
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Backup != nil && (state.Backup == nil || *plan.Backup != *state.Backup) {
+		// Connect to the target database
+		db, err := r.client.Database(ctx, plan.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+			return
+		}
+		if _, err := db.ExecContext(ctx, backupCertificateStatement(&plan)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("backup"), "Error backing up certificate", err.Error())
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+// Backup files are left on the server.
+func (r *certificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data certificateResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	if _, err := db.ExecContext(ctx, "DROP CERTIFICATE "+quoteIdentifier(data.Name.ValueString())); err != nil {
+		resp.Diagnostics.AddError("Error dropping certificate", err.Error())
+		return
+	}
+}
+
+// ImportState imports a certificate from an ID of the form database/name.
+// Passwords and file paths are not stored on the server.
+func (r *certificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/name")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *certificateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCreateCertificateStatement(t *testing.T) {
+	cases := []struct {
+		data certificateResourceModel
+		want string
+	}{
+		{
+			certificateResourceModel{
+				Name:       types.StringValue("tde"),
+				Subject:    types.StringValue("TDE certificate"),
+				ExpiryDate: types.StringUnknown(),
+			},
+			"CREATE CERTIFICATE [tde] WITH SUBJECT = N'TDE certificate'",
+		},
+		{
+			certificateResourceModel{
+				Name:               types.StringValue("tde"),
+				Subject:            types.StringValue("TDE certificate"),
+				ExpiryDate:         types.StringValue("2030-12-31T02:00:00+02:00"),
+				EncryptionPassword: types.StringValue("it's secret"),
+			},
+			"CREATE CERTIFICATE [tde] ENCRYPTION BY PASSWORD = N'it''s secret' WITH SUBJECT = N'TDE certificate', " +
+				"EXPIRY_DATE = N'2030-12-31T00:00:00'",
+		},
+		{
+			certificateResourceModel{
+				Name: types.StringValue("tde"),
+				FromFile: &certificateFilesModel{
+					CertificatePath:    types.StringValue("/backup/tde.cer"),
+					PrivateKeyPath:     types.StringValue("/backup/tde.pvk"),
+					PrivateKeyPassword: types.StringValue("file secret"),
+				},
+			},
+			"CREATE CERTIFICATE [tde] FROM FILE = N'/backup/tde.cer' " +
+				"WITH PRIVATE KEY (FILE = N'/backup/tde.pvk', DECRYPTION BY PASSWORD = N'file secret')",
+		},
+	}
+	for _, tc := range cases {
+		if got := createCertificateStatement(&tc.data); got != tc.want {
+			t.Errorf("createCertificateStatement() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestBackupCertificateStatement(t *testing.T) {
+	data := certificateResourceModel{
+		Name: types.StringValue("tde"),
+		Backup: &certificateFilesModel{
+			CertificatePath:    types.StringValue("/backup/tde.cer"),
+			PrivateKeyPath:     types.StringValue("/backup/tde.pvk"),
+			PrivateKeyPassword: types.StringValue("file secret"),
+		},
+	}
+	want := "BACKUP CERTIFICATE [tde] TO FILE = N'/backup/tde.cer' " +
+		"WITH PRIVATE KEY (FILE = N'/backup/tde.pvk', ENCRYPTION BY PASSWORD = N'file secret')"
+	if got := backupCertificateStatement(&data); got != want {
+		t.Errorf("backupCertificateStatement() = %q, want %q", got, want)
+	}
+
+	data.Backup.PrivateKeyPath = types.StringNull()
+	want = "BACKUP CERTIFICATE [tde] TO FILE = N'/backup/tde.cer'"
+	if got := backupCertificateStatement(&data); got != want {
+		t.Errorf("backupCertificateStatement() = %q, want %q", got, want)
+	}
+}
+
+func TestCertificateDate(t *testing.T) {
+	actual := time.Date(2030, 12, 31, 0, 0, 0, 0, time.Local)
+	cases := []struct {
+		prior types.String
+		want  types.String
+	}{
+		{types.StringUnknown(), types.StringValue("2030-12-31T00:00:00Z")},
+		{types.StringValue("2030-12-31T02:00:00+02:00"), types.StringValue("2030-12-31T02:00:00+02:00")},
+		{types.StringValue("2031-12-31T00:00:00Z"), types.StringValue("2030-12-31T00:00:00Z")},
+	}
+	for _, tc := range cases {
+		if got := certificateDate(tc.prior, actual); !got.Equal(tc.want) {
+			t.Errorf("certificateDate(%s) = %s, want %s", tc.prior, got, tc.want)
+		}
+	}
+}
+
+func TestEncryptionAlgorithm(t *testing.T) {
+	if got := encryptionAlgorithm("AES", 256); got != "AES_256" {
+		t.Errorf("encryptionAlgorithm(AES, 256) = %q, want AES_256", got)
+	}
+	if got := encryptionAlgorithm("TRIPLE_DES_3KEY", 192); got != "TRIPLE_DES_3KEY" {
+		t.Errorf("encryptionAlgorithm(TRIPLE_DES_3KEY, 192) = %q, want TRIPLE_DES_3KEY", got)
+	}
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &changeTrackingTableResource{}
+	_ resource.ResourceWithConfigure   = &changeTrackingTableResource{}
+	_ resource.ResourceWithImportState = &changeTrackingTableResource{}
+)
+
+// NewMssqlChangeTrackingTableResource a helper function to simplify the provider implementation.
+func NewMssqlChangeTrackingTableResource() resource.Resource {
+	return &changeTrackingTableResource{}
+}
+
+// maps to resource schema table
+type changeTrackingTableResourceModel struct {
+	Database            types.String `tfsdk:"database"`
+	Schema              types.String `tfsdk:"schema"`
+	Table               types.String `tfsdk:"table"`
+	TrackColumnsUpdated types.Bool   `tfsdk:"track_columns_updated"`
+	Id                  types.String `tfsdk:"id"`
+}
+
+// changeTrackingTableResource is the resource implementation.
+type changeTrackingTableResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *changeTrackingTableResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_change_tracking_table"
+}
+
+// Schema defines the schema for the resource.
+func (r *changeTrackingTableResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Change tracking table resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database name. Change tracking must be enabled on the database with the `change_tracking` block of `mssql_database`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Schema of the table. Defaults to `dbo`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("dbo"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table": schema.StringAttribute{
+				MarkdownDescription: "Table name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"track_columns_updated": schema.BoolAttribute{
+				MarkdownDescription: "Whether the columns changed by updates are recorded (`TRACK_COLUMNS_UPDATED`).",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier, in the form `database/schema/table`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *changeTrackingTableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data changeTrackingTableResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ENABLE CHANGE_TRACKING WITH (TRACK_COLUMNS_UPDATED = %s)",
+		qualifiedName(data.Schema.ValueString(), data.Table.ValueString()), onOff(data.TrackColumnsUpdated.ValueBool())))
+	if err != nil {
+		detail := err.Error()
+		if hasSQLError(err, 4997) {
+			detail += "\n\nEnable change tracking on the database first, with the change_tracking block of mssql_database."
+		}
+		resp.Diagnostics.AddError("Error enabling change tracking", detail)
+		return
+	}
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Schema.ValueString(), data.Table.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *changeTrackingTableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state changeTrackingTableResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+
+	var trackColumnsUpdated bool
+	err = db.QueryRowContext(ctx, `
+		SELECT is_track_columns_updated_on
+		FROM sys.change_tracking_tables
+		WHERE object_id = OBJECT_ID(@p1)`,
+		qualifiedName(state.Schema.ValueString(), state.Table.ValueString())).Scan(&trackColumnsUpdated)
+	if err == sql.ErrNoRows {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error reading change tracking", err.Error())
+		return
+	}
+	state.TrackColumnsUpdated = types.BoolValue(trackColumnsUpdated)
+	state.Id = types.StringValue(joinId(state.Database.ValueString(), state.Schema.ValueString(), state.Table.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Every attribute forces replacement, so there is nothing to apply.
+func (r *changeTrackingTableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan changeTrackingTableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...) // Read plan
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *changeTrackingTableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data changeTrackingTableResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s DISABLE CHANGE_TRACKING",
+		qualifiedName(data.Schema.ValueString(), data.Table.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("Error disabling change tracking", err.Error())
+		return
+	}
+}
+
+// ImportState imports a table from an ID of the form database/schema/table.
+func (r *changeTrackingTableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/schema/table")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("schema"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("table"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *changeTrackingTableResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
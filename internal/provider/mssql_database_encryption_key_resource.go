@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &databaseEncryptionKeyResource{}
+	_ resource.ResourceWithConfigure      = &databaseEncryptionKeyResource{}
+	_ resource.ResourceWithImportState    = &databaseEncryptionKeyResource{}
+	_ resource.ResourceWithValidateConfig = &databaseEncryptionKeyResource{}
+)
+
+// encryptionStates names the encryption_state values of
+// sys.dm_database_encryption_keys. encryption_state_desc only exists from
+// SQL Server 2019 on.
+var encryptionStates = map[int]string{
+	0: "NONE",
+	1: "UNENCRYPTED",
+	2: "ENCRYPTION_IN_PROGRESS",
+	3: "ENCRYPTED",
+	4: "KEY_CHANGE_IN_PROGRESS",
+	5: "DECRYPTION_IN_PROGRESS",
+	6: "PROTECTION_CHANGE_IN_PROGRESS",
+}
+
+const (
+	encryptionStateUnencrypted  = 1
+	encryptionStatePollInterval = 5 * time.Second
+)
+
+// NewMssqlDatabaseEncryptionKeyResource a helper function to simplify the provider implementation.
+func NewMssqlDatabaseEncryptionKeyResource() resource.Resource {
+	return &databaseEncryptionKeyResource{}
+}
+
+// maps to resource schema table
+type databaseEncryptionKeyResourceModel struct {
+	Database          types.String `tfsdk:"database"`
+	Algorithm         types.String `tfsdk:"algorithm"`
+	ServerCertificate types.String `tfsdk:"server_certificate"`
+	EncryptionEnabled types.Bool   `tfsdk:"encryption_enabled"`
+	EncryptionState   types.String `tfsdk:"encryption_state"`
+	Id                types.String `tfsdk:"id"`
+}
+
+// databaseEncryptionKeyResource is the resource implementation.
+type databaseEncryptionKeyResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *databaseEncryptionKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_encryption_key"
+}
+
+// Schema defines the schema for the resource.
+func (r *databaseEncryptionKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Database encryption key resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Name of the database to encrypt",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "Encryption algorithm: `AES_128`, `AES_192`, `AES_256` or `TRIPLE_DES_3KEY`. " +
+					"Defaults to `AES_256`. Changing it regenerates the key, which re-encrypts the database in the background.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("AES_256"),
+			},
+			"server_certificate": schema.StringAttribute{
+				MarkdownDescription: "Name of the certificate in `master` that protects the key. Changing it re-encrypts " +
+					"only the key. Back the certificate up: without it the database cannot be restored elsewhere.",
+				Required: true,
+			},
+			"encryption_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the database is encrypted (`SET ENCRYPTION`). Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"encryption_state": schema.StringAttribute{
+				MarkdownDescription: "Encryption state reported by `sys.dm_database_encryption_keys`, such as " +
+					"`ENCRYPTION_IN_PROGRESS` or `ENCRYPTED`. Encryption runs in the background after apply.",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Encryption key identifier, the database name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *databaseEncryptionKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data databaseEncryptionKeyResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	validateOneOf(&resp.Diagnostics, path.Root("algorithm"), data.Algorithm, "AES_128", "AES_192", "AES_256", "TRIPLE_DES_3KEY")
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *databaseEncryptionKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data databaseEncryptionKeyResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	algorithm := strings.ToUpper(data.Algorithm.ValueString())
+	if err := validateKeyword("algorithm", algorithm); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("algorithm"), "Invalid algorithm", err.Error())
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE ENCRYPTION KEY WITH ALGORITHM = %s ENCRYPTION BY SERVER CERTIFICATE %s",
+		algorithm, quoteIdentifier(data.ServerCertificate.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating database encryption key", err.Error())
+		return
+	}
+	if data.EncryptionEnabled.ValueBool() {
+		if err := r.setEncryption(ctx, data.Database.ValueString(), true); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("encryption_enabled"), "Error enabling encryption", err.Error())
+			return
+		}
+	}
+
+	found, err := r.read(ctx, &data)
+	if err != nil || !found {
+		if err == nil {
+			err = fmt.Errorf("the encryption key of database %q was not found after it was created", data.Database.ValueString())
+		}
+		resp.Diagnostics.AddError("Error reading database encryption key", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+func (r *databaseEncryptionKeyResource) setEncryption(ctx context.Context, database string, enabled bool) error {
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s SET ENCRYPTION %s", quoteIdentifier(database), onOff(enabled)))
+	return err
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *databaseEncryptionKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state databaseEncryptionKeyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading database encryption key", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// read populates data from sys.dm_database_encryption_keys. It reports false
+// if the database has no encryption key.
+func (r *databaseEncryptionKeyResource) read(ctx context.Context, data *databaseEncryptionKeyResourceModel) (bool, error) {
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		state       int
+		algorithm   string
+		keyLength   int
+		certificate sql.NullString
+		encrypted   bool
+	)
+	err = db.QueryRowContext(ctx, `
+		SELECT k.encryption_state, k.key_algorithm, k.key_length, c.name, d.is_encrypted
+		FROM sys.dm_database_encryption_keys k
+		JOIN sys.databases d ON d.database_id = k.database_id
+		LEFT JOIN sys.certificates c ON c.thumbprint = k.encryptor_thumbprint
+		WHERE k.database_id = DB_ID(@p1)`, data.Database.ValueString()).
+		Scan(&state, &algorithm, &keyLength, &certificate, &encrypted)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	data.Algorithm = keepCase(data.Algorithm, encryptionAlgorithm(algorithm, keyLength))
+	data.ServerCertificate = types.StringNull()
+	if certificate.Valid {
+		data.ServerCertificate = keepCase(data.ServerCertificate, certificate.String)
+	}
+	data.EncryptionEnabled = types.BoolValue(encrypted)
+	data.EncryptionState = types.StringValue(encryptionStateName(state))
+	data.Id = types.StringValue(data.Database.ValueString())
+	return true, nil
+}
+
+// encryptionAlgorithm maps key_algorithm and key_length to the ALGORITHM
+// keyword of CREATE DATABASE ENCRYPTION KEY.
+func encryptionAlgorithm(algorithm string, keyLength int) string {
+	if strings.EqualFold(algorithm, "AES") {
+		return fmt.Sprintf("AES_%d", keyLength)
+	}
+	return strings.ToUpper(algorithm)
+}
+
+func encryptionStateName(state int) string {
+	if name, ok := encryptionStates[state]; ok {
+		return name
+	}
+	return fmt.Sprint(state)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *databaseEncryptionKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state databaseEncryptionKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...) // Read plan
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var changes []string
+	if !strings.EqualFold(plan.Algorithm.ValueString(), state.Algorithm.ValueString()) {
+		algorithm := strings.ToUpper(plan.Algorithm.ValueString())
+		if err := validateKeyword("algorithm", algorithm); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("algorithm"), "Invalid algorithm", err.Error())
+			return
+		}
+		changes = append(changes, "REGENERATE WITH ALGORITHM = "+algorithm)
+	}
+	if !strings.EqualFold(plan.ServerCertificate.ValueString(), state.ServerCertificate.ValueString()) {
+		changes = append(changes, "ENCRYPTION BY SERVER CERTIFICATE "+quoteIdentifier(plan.ServerCertificate.ValueString()))
+	}
+	if len(changes) > 0 {
+		// Connect to the target database
+		db, err := r.client.Database(ctx, plan.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+			return
+		}
+		// Only one key operation may run at a time, so each change is its own
+		// statement.
+		for _, change := range changes {
+			if _, err := db.ExecContext(ctx, "ALTER DATABASE ENCRYPTION KEY "+change); err != nil {
+				resp.Diagnostics.AddError("Error changing database encryption key", err.Error())
+				return
+			}
+		}
+	}
+	if !plan.EncryptionEnabled.Equal(state.EncryptionEnabled) {
+		if err := r.setEncryption(ctx, plan.Database.ValueString(), plan.EncryptionEnabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("encryption_enabled"), "Error changing encryption", err.Error())
+			return
+		}
+	}
+
+	found, err := r.read(ctx, &plan)
+	if err != nil || !found {
+		if err == nil {
+			err = fmt.Errorf("the encryption key of database %q was not found", plan.Database.ValueString())
+		}
+		resp.Diagnostics.AddError("Error reading database encryption key", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete deletes the resource and removes the Terraform state on success. The
+// key can only be dropped once the database is fully decrypted, so encryption
+// is turned off first and Delete waits for the background scan to finish.
+func (r *databaseEncryptionKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data databaseEncryptionKeyResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	database := data.Database.ValueString()
+	if err := r.setEncryption(ctx, database, false); err != nil {
+		resp.Diagnostics.AddError("Error disabling encryption", err.Error())
+		return
+	}
+	if err := r.waitForDecryption(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Error disabling encryption", err.Error())
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, database)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	if _, err := db.ExecContext(ctx, "DROP DATABASE ENCRYPTION KEY"); err != nil {
+		resp.Diagnostics.AddError("Error dropping database encryption key", err.Error())
+		return
+	}
+}
+
+func (r *databaseEncryptionKeyResource) waitForDecryption(ctx context.Context, data *databaseEncryptionKeyResourceModel) error {
+	for {
+		current := databaseEncryptionKeyResourceModel{Database: data.Database}
+		found, err := r.read(ctx, &current)
+		if err != nil {
+			return err
+		}
+		if !found || current.EncryptionState.ValueString() == encryptionStates[encryptionStateUnencrypted] {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the database to be decrypted: %w", ctx.Err())
+		case <-time.After(encryptionStatePollInterval):
+		}
+	}
+}
+
+// ImportState imports the encryption key of the database named by the ID.
+func (r *databaseEncryptionKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *databaseEncryptionKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &databaseScopedConfigurationResource{}
+	_ resource.ResourceWithConfigure      = &databaseScopedConfigurationResource{}
+	_ resource.ResourceWithImportState    = &databaseScopedConfigurationResource{}
+	_ resource.ResourceWithValidateConfig = &databaseScopedConfigurationResource{}
+)
+
+// scopedConfigurationDefaults holds the values configurations are reset to
+// when the resource is destroyed. SQL Server has no statement to reset a
+// single configuration, so others are left as they are.
+var scopedConfigurationDefaults = map[string]string{
+	"MAXDOP":                                        "0",
+	"LEGACY_CARDINALITY_ESTIMATION":                 "OFF",
+	"PARAMETER_SNIFFING":                            "ON",
+	"QUERY_OPTIMIZER_HOTFIXES":                      "OFF",
+	"OPTIMIZE_FOR_AD_HOC_WORKLOADS":                 "OFF",
+	"IDENTITY_CACHE":                                "ON",
+	"XTP_PROCEDURE_EXECUTION_STATISTICS":            "OFF",
+	"XTP_QUERY_EXECUTION_STATISTICS":                "OFF",
+	"BATCH_MODE_ON_ROWSTORE":                        "ON",
+	"BATCH_MODE_MEMORY_GRANT_FEEDBACK":              "ON",
+	"BATCH_MODE_ADAPTIVE_JOINS":                     "ON",
+	"TSQL_SCALAR_UDF_INLINING":                      "ON",
+	"INTERLEAVED_EXECUTION_TVF":                     "ON",
+	"ROW_MODE_MEMORY_GRANT_FEEDBACK":                "ON",
+	"DEFERRED_COMPILATION_TV":                       "ON",
+	"LIGHTWEIGHT_QUERY_PROFILING":                   "ON",
+	"LAST_QUERY_PLAN_STATS":                         "OFF",
+	"VERBOSE_TRUNCATION_WARNINGS":                   "ON",
+	"ACCELERATED_PLAN_FORCING":                      "ON",
+	"ELEVATE_ONLINE":                                "OFF",
+	"ELEVATE_RESUMABLE":                             "OFF",
+	"ISOLATE_SECURITY_POLICY_CARDINALITY":           "OFF",
+	"GLOBAL_TEMPORARY_TABLE_AUTO_DROP":              "ON",
+	"PAUSED_RESUMABLE_INDEX_ABORT_DURATION_MINUTES": "1440",
+}
+
+// valueForPrimary is the value_for_secondary keyword that makes secondaries
+// use the primary's value. SQL Server reports it as NULL.
+const valueForPrimary = "PRIMARY"
+
+// NewMssqlDatabaseScopedConfigurationResource a helper function to simplify the provider implementation.
+func NewMssqlDatabaseScopedConfigurationResource() resource.Resource {
+	return &databaseScopedConfigurationResource{}
+}
+
+// maps to resource schema table
+type databaseScopedConfigurationResourceModel struct {
+	Database          types.String `tfsdk:"database"`
+	Name              types.String `tfsdk:"name"`
+	Value             types.String `tfsdk:"value"`
+	ValueForSecondary types.String `tfsdk:"value_for_secondary"`
+	Id                types.String `tfsdk:"id"`
+}
+
+// databaseScopedConfigurationResource is the resource implementation.
+type databaseScopedConfigurationResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *databaseScopedConfigurationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_scoped_configuration"
+}
+
+// Schema defines the schema for the resource.
+func (r *databaseScopedConfigurationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Database scoped configuration resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Configuration name, such as `MAXDOP` or `LEGACY_CARDINALITY_ESTIMATION`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Value for the primary replica. `ON` and `OFF` match `1` and `0` as reported by SQL Server.",
+				Required:            true,
+			},
+			"value_for_secondary": schema.StringAttribute{
+				MarkdownDescription: "Value for secondary replicas (`FOR SECONDARY`), or `PRIMARY` to use the primary's value. " +
+					"Only some configurations, such as `MAXDOP`, support it.",
+				Optional: true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Configuration identifier, in the form `database/name`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the configuration name, which is emitted unquoted.
+func (r *databaseScopedConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data databaseScopedConfigurationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Name.IsNull() && !data.Name.IsUnknown() {
+		if err := validateKeyword("configuration name", data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid configuration name", err.Error())
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *databaseScopedConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data databaseScopedConfigurationResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	if err := r.set(ctx, db, &data, nil); err != nil {
+		resp.Diagnostics.AddError("Error setting database scoped configuration", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Name.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+// set applies the values of plan that differ from state. state is nil on
+// create.
+func (r *databaseScopedConfigurationResource) set(ctx context.Context, db *sql.DB, plan, state *databaseScopedConfigurationResourceModel) error {
+	name := plan.Name.ValueString()
+	if err := validateKeyword("configuration name", name); err != nil {
+		return err
+	}
+
+	if state == nil || !plan.Value.Equal(state.Value) {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE SCOPED CONFIGURATION SET %s = %s",
+			name, scopedConfigurationValue(plan.Value.ValueString())))
+		if err != nil {
+			return err
+		}
+	}
+
+	secondary := plan.ValueForSecondary
+	if secondary.IsNull() {
+		// Only reset secondaries when the configuration stops managing them.
+		if state == nil || state.ValueForSecondary.IsNull() {
+			return nil
+		}
+		secondary = types.StringValue(valueForPrimary)
+	}
+	if state == nil || !secondary.Equal(state.ValueForSecondary) {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE SCOPED CONFIGURATION FOR SECONDARY SET %s = %s",
+			name, scopedConfigurationValue(secondary.ValueString())))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scopedConfigurationValue renders a value as a keyword, such as ON, 8 or
+// WHEN_SUPPORTED, or as a string literal for values like storage endpoints.
+func scopedConfigurationValue(value string) string {
+	if keywordPattern.MatchString(value) {
+		return value
+	}
+	return quoteString(value)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *databaseScopedConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state databaseScopedConfigurationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+
+	var (
+		name                     string
+		value, valueForSecondary sql.NullString
+	)
+	err = db.QueryRowContext(ctx, `
+		SELECT name, CAST(value AS nvarchar(4000)), CAST(value_for_secondary AS nvarchar(4000))
+		FROM sys.database_scoped_configurations
+		WHERE name = @p1`, state.Name.ValueString()).Scan(&name, &value, &valueForSecondary)
+	if err == sql.ErrNoRows {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error reading database scoped configuration", err.Error())
+		return
+	}
+
+	state.Name = keepCase(state.Name, name)
+	state.Value = scopedConfigurationState(state.Value, value)
+	if valueForSecondary.Valid || !strings.EqualFold(state.ValueForSecondary.ValueString(), valueForPrimary) {
+		state.ValueForSecondary = scopedConfigurationState(state.ValueForSecondary, valueForSecondary)
+	}
+	state.Id = types.StringValue(joinId(state.Database.ValueString(), state.Name.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// scopedConfigurationState returns prior when it names the value SQL Server
+// reports as actual, so ON/OFF settings read back as 1/0 do not show up as
+// drift.
+func scopedConfigurationState(prior types.String, actual sql.NullString) types.String {
+	if !actual.Valid {
+		return types.StringNull()
+	}
+	if !prior.IsNull() && !prior.IsUnknown() {
+		switch p := prior.ValueString(); {
+		case strings.EqualFold(p, actual.String),
+			strings.EqualFold(p, "ON") && actual.String == "1",
+			strings.EqualFold(p, "OFF") && actual.String == "0":
+			return prior
+		}
+	}
+	return types.StringValue(actual.String)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *databaseScopedConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan databaseScopedConfigurationResourceModel
+	var state databaseScopedConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)   // Read plan
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...) // Read state
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, plan.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	if err := r.set(ctx, db, &plan, &state); err != nil {
+		resp.Diagnostics.AddError("Error setting database scoped configuration", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete resets the configuration to its default and removes the Terraform
+// state on success.
+func (r *databaseScopedConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data databaseScopedConfigurationResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := strings.ToUpper(data.Name.ValueString())
+	value, ok := scopedConfigurationDefaults[name]
+	if !ok {
+		resp.Diagnostics.AddWarning("Database scoped configuration left unchanged",
+			fmt.Sprintf("The provider does not know the default of %s, so it keeps its current value in database %q.",
+				name, data.Database.ValueString()))
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	reset := databaseScopedConfigurationResourceModel{
+		Name:  data.Name,
+		Value: types.StringValue(value),
+	}
+	if err := r.set(ctx, db, &reset, &data); err != nil {
+		resp.Diagnostics.AddError("Error resetting database scoped configuration", err.Error())
+		return
+	}
+}
+
+// ImportState imports a configuration from an ID of the form database/name.
+func (r *databaseScopedConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/name")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *databaseScopedConfigurationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestScopedConfigurationState(t *testing.T) {
+	cases := []struct {
+		prior  types.String
+		actual sql.NullString
+		want   types.String
+	}{
+		{types.StringValue("ON"), sql.NullString{String: "1", Valid: true}, types.StringValue("ON")},
+		{types.StringValue("off"), sql.NullString{String: "0", Valid: true}, types.StringValue("off")},
+		{types.StringValue("ON"), sql.NullString{String: "0", Valid: true}, types.StringValue("0")},
+		{types.StringValue("8"), sql.NullString{String: "8", Valid: true}, types.StringValue("8")},
+		{types.StringValue("8"), sql.NullString{String: "4", Valid: true}, types.StringValue("4")},
+		{types.StringValue("when_supported"), sql.NullString{String: "WHEN_SUPPORTED", Valid: true}, types.StringValue("when_supported")},
+		{types.StringNull(), sql.NullString{String: "1", Valid: true}, types.StringValue("1")},
+		{types.StringValue("PRIMARY"), sql.NullString{}, types.StringNull()},
+	}
+	for _, tc := range cases {
+		if got := scopedConfigurationState(tc.prior, tc.actual); !got.Equal(tc.want) {
+			t.Errorf("scopedConfigurationState(%s, %q) = %s, want %s", tc.prior, tc.actual.String, got, tc.want)
+		}
+	}
+}
+
+func TestScopedConfigurationValue(t *testing.T) {
+	cases := map[string]string{
+		"ON":                      "ON",
+		"8":                       "8",
+		"WHEN_SUPPORTED":          "WHEN_SUPPORTED",
+		"https://example.net/x":   "N'https://example.net/x'",
+		"0; DROP DATABASE master": "N'0; DROP DATABASE master'",
+	}
+	for in, want := range cases {
+		if got := scopedConfigurationValue(in); got != want {
+			t.Errorf("scopedConfigurationValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &databaseSnapshotResource{}
+	_ resource.ResourceWithConfigure   = &databaseSnapshotResource{}
+	_ resource.ResourceWithImportState = &databaseSnapshotResource{}
+)
+
+// NewMssqlDatabaseSnapshotResource a helper function to simplify the provider implementation.
+func NewMssqlDatabaseSnapshotResource() resource.Resource {
+	return &databaseSnapshotResource{}
+}
+
+// maps to resource schema table
+type databaseSnapshotResourceModel struct {
+	Name            types.String `tfsdk:"name"`
+	SourceDatabase  types.String `tfsdk:"source_database"`
+	Directory       types.String `tfsdk:"directory"`
+	RevertOnDestroy types.Bool   `tfsdk:"revert_on_destroy"`
+	Id              types.String `tfsdk:"id"`
+}
+
+// databaseSnapshotResource is the resource implementation.
+type databaseSnapshotResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *databaseSnapshotResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_snapshot"
+}
+
+// Schema defines the schema for the resource.
+func (r *databaseSnapshotResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Database snapshot resource",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Snapshot name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_database": schema.StringAttribute{
+				MarkdownDescription: "Name of the database to snapshot",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"directory": schema.StringAttribute{
+				MarkdownDescription: "Directory on the server for the sparse files of the snapshot. " +
+					"Defaults to the directory of each data file of the source database.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"revert_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroying the snapshot first reverts the source database to it. " +
+					"Reverting discards every change made to the source database since the snapshot was taken, and fails " +
+					"when the source database has more than one snapshot.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Snapshot identifier.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *databaseSnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data databaseSnapshotResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	// Connect to the source database
+	source, err := r.client.Database(ctx, data.SourceDatabase.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+
+	// A snapshot needs one sparse file for every data file of the source.
+	rows, err := source.QueryContext(ctx, `
+		SELECT name, physical_name FROM sys.database_files WHERE type = @p1 ORDER BY file_id`, databaseFileTypeRows)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading source database files", err.Error())
+		return
+	}
+	var files []snapshotSourceFile
+	for rows.Next() {
+		var f snapshotSourceFile
+		if err := rows.Scan(&f.logicalName, &f.physicalName); err != nil {
+			rows.Close()
+			resp.Diagnostics.AddError("Error reading source database files", err.Error())
+			return
+		}
+		files = append(files, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		resp.Diagnostics.AddError("Error reading source database files", err.Error())
+		return
+	}
+
+	_, err = db.ExecContext(ctx, createSnapshotStatement(&data, files))
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating database snapshot", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(data.Name.ValueString())
+	if _, err := r.read(ctx, db, &data); err != nil {
+		resp.Diagnostics.AddError("Error reading database snapshot", err.Error())
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *databaseSnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state databaseSnapshotResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+
+	found, err := r.read(ctx, db, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading database snapshot", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// read populates data from sys.databases and sys.master_files. It reports
+// false if the snapshot does not exist. A database of the same name that is
+// not a snapshot, or is a snapshot of another database, is reported as an
+// error rather than adopted.
+func (r *databaseSnapshotResource) read(ctx context.Context, db *sql.DB, data *databaseSnapshotResourceModel) (bool, error) {
+	var (
+		name   string
+		source sql.NullString
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT d.name, s.name
+		FROM sys.databases d
+		LEFT JOIN sys.databases s ON s.database_id = d.source_database_id
+		WHERE d.name = @p1`, data.Name.ValueString()).Scan(&name, &source)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if !source.Valid {
+		return false, fmt.Errorf("database %q is not a database snapshot", name)
+	}
+
+	data.Name = keepCase(data.Name, name)
+	data.SourceDatabase = keepCase(data.SourceDatabase, source.String)
+	data.Id = types.StringValue(data.Name.ValueString())
+	if data.RevertOnDestroy.IsNull() {
+		data.RevertOnDestroy = types.BoolValue(false)
+	}
+
+	// Report the directory only when every sparse file is in the same one.
+	rows, err := db.QueryContext(ctx, `
+		SELECT physical_name FROM sys.master_files WHERE database_id = DB_ID(@p1) AND type = @p2`,
+		data.Name.ValueString(), databaseFileTypeRows)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	dirs := map[string]bool{}
+	var dir string
+	for rows.Next() {
+		var physical string
+		if err := rows.Scan(&physical); err != nil {
+			return false, err
+		}
+		dir = directoryOf(physical)
+		dirs[strings.ToLower(dir)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	switch {
+	case len(dirs) != 1:
+		data.Directory = types.StringNull()
+	case !data.Directory.IsNull() && !data.Directory.IsUnknown() &&
+		strings.EqualFold(strings.TrimRight(data.Directory.ValueString(), `/\`), strings.TrimRight(dir, `/\`)):
+		// Keep the configured spelling, with or without a trailing separator.
+	default:
+		data.Directory = types.StringValue(dir)
+	}
+	return true, nil
+}
+
+// snapshotSourceFile is a data file of the source database.
+type snapshotSourceFile struct {
+	logicalName  string
+	physicalName string
+}
+
+// createSnapshotStatement builds the CREATE DATABASE ... AS SNAPSHOT OF
+// statement, with one sparse file for every data file of the source. Sparse
+// files are named after the snapshot and the logical file name, and placed in
+// data.Directory or next to the source file.
+func createSnapshotStatement(data *databaseSnapshotResourceModel, files []snapshotSourceFile) string {
+	specs := make([]string, 0, len(files))
+	for _, f := range files {
+		dir := data.Directory.ValueString()
+		if !known(data.Directory) {
+			dir = directoryOf(f.physicalName)
+		} else if !strings.HasSuffix(dir, "/") && !strings.HasSuffix(dir, `\`) {
+			dir += separatorOf(dir)
+		}
+		sparse := dir + data.Name.ValueString() + "_" + f.logicalName + ".ss"
+		specs = append(specs, fmt.Sprintf("(NAME = %s, FILENAME = %s)", quoteString(f.logicalName), quoteString(sparse)))
+	}
+	return fmt.Sprintf("CREATE DATABASE %s ON %s AS SNAPSHOT OF %s",
+		quoteIdentifier(data.Name.ValueString()), strings.Join(specs, ", "), quoteIdentifier(data.SourceDatabase.ValueString()))
+}
+
+// directoryOf returns the directory part of a server file path, including
+// the trailing separator.
+func directoryOf(physical string) string {
+	return physical[:strings.LastIndexAny(physical, `/\`)+1]
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Only revert_on_destroy can change in place, and it is not stored on the server.
+func (r *databaseSnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan databaseSnapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...) // Read plan
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *databaseSnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data databaseSnapshotResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+
+	if data.RevertOnDestroy.ValueBool() {
+		// Reverting needs exclusive access to the source database.
+		r.client.Release(data.SourceDatabase.ValueString())
+		_, err = db.ExecContext(ctx, fmt.Sprintf("RESTORE DATABASE %s FROM DATABASE_SNAPSHOT = %s",
+			quoteIdentifier(data.SourceDatabase.ValueString()), quoteString(data.Name.ValueString())))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reverting database to snapshot", err.Error())
+			return
+		}
+	}
+
+	r.client.Release(data.Name.ValueString())
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE %s", quoteIdentifier(data.Name.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting database snapshot", err.Error())
+		return
+	}
+}
+
+// ImportState imports a snapshot by name. Read fills in the remaining
+// attributes.
+func (r *databaseSnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *databaseSnapshotResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
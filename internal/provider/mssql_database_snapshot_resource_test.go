@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCreateSnapshotStatement(t *testing.T) {
+	cases := []struct {
+		data  databaseSnapshotResourceModel
+		files []snapshotSourceFile
+		want  string
+	}{
+		{
+			databaseSnapshotResourceModel{
+				Name:           types.StringValue("app_snap"),
+				SourceDatabase: types.StringValue("app"),
+				Directory:      types.StringNull(),
+			},
+			[]snapshotSourceFile{
+				{logicalName: "app", physicalName: `C:\Program Files\MSSQL\DATA\app.mdf`},
+				{logicalName: "app_data2", physicalName: `D:\Data\app_data2.ndf`},
+			},
+			`CREATE DATABASE [app_snap] ON (NAME = N'app', FILENAME = N'C:\Program Files\MSSQL\DATA\app_snap_app.ss'), ` +
+				`(NAME = N'app_data2', FILENAME = N'D:\Data\app_snap_app_data2.ss') AS SNAPSHOT OF [app]`,
+		},
+		{
+			databaseSnapshotResourceModel{
+				Name:           types.StringValue("app_snap"),
+				SourceDatabase: types.StringValue("app"),
+				Directory:      types.StringValue("/var/opt/mssql/snapshots"),
+			},
+			[]snapshotSourceFile{
+				{logicalName: "app", physicalName: "/var/opt/mssql/data/app.mdf"},
+				{logicalName: "app_data2", physicalName: "/data/app_data2.ndf"},
+			},
+			"CREATE DATABASE [app_snap] ON (NAME = N'app', FILENAME = N'/var/opt/mssql/snapshots/app_snap_app.ss'), " +
+				"(NAME = N'app_data2', FILENAME = N'/var/opt/mssql/snapshots/app_snap_app_data2.ss') AS SNAPSHOT OF [app]",
+		},
+		{
+			databaseSnapshotResourceModel{
+				Name:           types.StringValue("snap]1"),
+				SourceDatabase: types.StringValue("o'brien]db"),
+				Directory:      types.StringValue(`E:\Snapshots\`),
+			},
+			[]snapshotSourceFile{
+				{logicalName: "o'brien", physicalName: `C:\Data\obrien.mdf`},
+			},
+			`CREATE DATABASE [snap]]1] ON (NAME = N'o''brien', FILENAME = N'E:\Snapshots\snap]1_o''brien.ss') AS SNAPSHOT OF [o'brien]]db]`,
+		},
+	}
+	for _, tc := range cases {
+		if got := createSnapshotStatement(&tc.data, tc.files); got != tc.want {
+			t.Errorf("createSnapshotStatement(%q) =\n%s\nwant\n%s", tc.data.Name.ValueString(), got, tc.want)
+		}
+	}
+}
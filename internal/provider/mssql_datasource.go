@@ -5,7 +5,6 @@ package provider
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -21,7 +20,7 @@ func NewMssqlDataSource() datasource.DataSource {
 }
 
 type mssqlDataSource struct {
-	client *sql.DB
+	client *mssqlClient
 }
 
 type mssqlDataSourceModel struct {
@@ -53,11 +52,11 @@ func (d *mssqlDataSource) Configure(ctx context.Context, req datasource.Configur
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*sql.DB)
+	client, ok := req.ProviderData.(*mssqlClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *sql.DB, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mssqlClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -67,10 +66,16 @@ func (d *mssqlDataSource) Configure(ctx context.Context, req datasource.Configur
 func (d *mssqlDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data mssqlDataSourceModel
 
+	db, err := d.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+
 	// Get SQL Server version
-	row := d.client.QueryRowContext(ctx, "SELECT @@VERSION")
+	row := db.QueryRowContext(ctx, "SELECT @@VERSION")
 	var version string
-	err := row.Scan(&version)
+	err = row.Scan(&version)
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading SQL Server version", err.Error())
 		return
@@ -0,0 +1,470 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &filegroupResource{}
+	_ resource.ResourceWithConfigure      = &filegroupResource{}
+	_ resource.ResourceWithImportState    = &filegroupResource{}
+	_ resource.ResourceWithValidateConfig = &filegroupResource{}
+)
+
+// Filegroup types, as accepted by the provider.
+const (
+	filegroupTypeRows                = "ROWS"
+	filegroupTypeFilestream          = "FILESTREAM"
+	filegroupTypeMemoryOptimizedData = "MEMORY_OPTIMIZED_DATA"
+)
+
+// filegroupTypes maps sys.filegroups.type to the provider's filegroup types.
+var filegroupTypes = map[string]string{
+	"FG": filegroupTypeRows,
+	"FD": filegroupTypeFilestream,
+	"FX": filegroupTypeMemoryOptimizedData,
+}
+
+// NewMssqlFilegroupResource a helper function to simplify the provider implementation.
+func NewMssqlFilegroupResource() resource.Resource {
+	return &filegroupResource{}
+}
+
+// maps to resource schema table
+type filegroupResourceModel struct {
+	Database types.String        `tfsdk:"database"`
+	Name     types.String        `tfsdk:"name"`
+	Type     types.String        `tfsdk:"type"`
+	Default  types.Bool          `tfsdk:"default"`
+	ReadOnly types.Bool          `tfsdk:"read_only"`
+	Files    []databaseFileModel `tfsdk:"file"`
+	Id       types.String        `tfsdk:"id"`
+}
+
+// fgType returns the filegroup type in upper case, the configuration may
+// spell it in any case.
+func (m *filegroupResourceModel) fgType() string {
+	return strings.ToUpper(m.Type.ValueString())
+}
+
+// sized reports whether the filegroup's files have size settings. FILESTREAM
+// and memory-optimized filegroups hold directories instead of files.
+func (m *filegroupResourceModel) sized() bool {
+	return m.fgType() == filegroupTypeRows
+}
+
+// filegroupResource is the resource implementation.
+type filegroupResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *filegroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_filegroup"
+}
+
+// Schema defines the schema for the resource.
+func (r *filegroupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Filegroup resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Filegroup name",
+				Required:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Filegroup type: `ROWS`, `FILESTREAM` or `MEMORY_OPTIMIZED_DATA`. " +
+					"Memory-optimized filegroups cannot be removed once created, only dropped with their database.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(filegroupTypeRows),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"default": schema.BoolAttribute{
+				MarkdownDescription: "Whether new tables and indexes are created on this filegroup. " +
+					"Clearing it makes `PRIMARY` the default again. Not supported on memory-optimized filegroups.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether the filegroup is read-only. Changing it requires exclusive access to the database.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Filegroup identifier, in the form `database/name`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"file": databaseFileBlock("Files of the filegroup. For `FILESTREAM` and `MEMORY_OPTIMIZED_DATA` " +
+				"filegroups, `filename` is a container directory and the size attributes must not be set. " +
+				"Files that are not listed are emptied into the remaining files and removed."),
+		},
+	}
+}
+
+// ValidateConfig checks the filegroup type and its files.
+func (r *filegroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data filegroupResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateOneOf(&resp.Diagnostics, path.Root("type"), data.Type, filegroupTypeRows, filegroupTypeFilestream, filegroupTypeMemoryOptimizedData)
+	if data.Type.IsUnknown() {
+		return
+	}
+	if data.Type.IsNull() {
+		data.Type = types.StringValue(filegroupTypeRows)
+	}
+	validateDatabaseFiles(&resp.Diagnostics, path.Root("file"), data.Files, data.sized())
+	if data.fgType() == filegroupTypeMemoryOptimizedData && data.Default.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("default"), "Invalid default filegroup",
+			"A memory-optimized filegroup cannot be the default filegroup.")
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *filegroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data filegroupResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+
+	var contains string
+	switch data.fgType() {
+	case filegroupTypeFilestream:
+		contains = " CONTAINS FILESTREAM"
+	case filegroupTypeMemoryOptimizedData:
+		contains = " CONTAINS MEMORY_OPTIMIZED_DATA"
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s ADD FILEGROUP %s%s",
+		quoteIdentifier(data.Database.ValueString()), quoteIdentifier(data.Name.ValueString()), contains))
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating filegroup", err.Error())
+		return
+	}
+
+	// The filegroup exists from here on, so later failures keep it in state
+	// to be tainted rather than leaked.
+	created := filegroupResourceModel{
+		Database: data.Database,
+		Name:     data.Name,
+		Type:     data.Type,
+		Default:  types.BoolValue(false),
+		ReadOnly: types.BoolValue(false),
+		Files:    []databaseFileModel{},
+		Id:       types.StringValue(joinId(data.Database.ValueString(), data.Name.ValueString())),
+	}
+	resp.Diagnostics.Append(r.alter(ctx, db, &data, &created)...)
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &created)...)
+		return
+	}
+
+	data.Id = created.Id
+	if _, err := r.read(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Error reading filegroup", err.Error())
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *filegroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state filegroupResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading filegroup", err.Error())
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// read populates data from sys.filegroups and sys.database_files. It reports
+// false if the filegroup does not exist.
+func (r *filegroupResource) read(ctx context.Context, data *filegroupResourceModel) (bool, error) {
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		dataSpaceId       int
+		name, fgType      string
+		isDefault, isRead bool
+	)
+	err = db.QueryRowContext(ctx, `
+		SELECT data_space_id, name, type, is_default, is_read_only
+		FROM sys.filegroups
+		WHERE name = @p1`, data.Name.ValueString()).Scan(&dataSpaceId, &name, &fgType, &isDefault, &isRead)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	data.Name = keepCase(data.Name, name)
+	data.Type = keepCase(data.Type, filegroupTypes[fgType])
+	data.Default = types.BoolValue(isDefault)
+	data.ReadOnly = types.BoolValue(isRead)
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Name.ValueString()))
+
+	fileType := databaseFileTypeRows
+	if !data.sized() {
+		fileType = databaseFileTypeFilestream
+	}
+	files, err := readDatabaseFiles(ctx, db, fileType, dataSpaceId)
+	if err != nil {
+		return false, err
+	}
+	if !data.sized() {
+		for i := range files {
+			files[i].SizeMb = types.Int64Null()
+			files[i].MaxSizeMb = types.Int64Null()
+			files[i].GrowthMb = types.Int64Null()
+			files[i].GrowthPercent = types.Int64Null()
+		}
+	}
+	data.Files = mergeDatabaseFiles(data.Files, files)
+	return true, nil
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *filegroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan filegroupResourceModel
+	var state filegroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)   // Read plan
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...) // Read state
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(r.alter(ctx, db, &plan, &state)...)
+	if resp.Diagnostics.HasError() {
+		// Record the changes that were applied before the failure.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	if _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error reading filegroup", err.Error())
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// alter applies the differences between plan and state to an existing
+// filegroup, updating state as each change succeeds.
+func (r *filegroupResource) alter(ctx context.Context, db *sql.DB, plan, state *filegroupResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	database := quoteIdentifier(plan.Database.ValueString())
+
+	if plan.Name.ValueString() != state.Name.ValueString() {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY FILEGROUP %s NAME = %s",
+			database, quoteIdentifier(state.Name.ValueString()), quoteIdentifier(plan.Name.ValueString())))
+		if err != nil {
+			diags.AddAttributeError(path.Root("name"), "Error renaming filegroup", err.Error())
+			return diags
+		}
+		state.Name = plan.Name
+		state.Id = types.StringValue(joinId(plan.Database.ValueString(), plan.Name.ValueString()))
+	}
+	filegroup := quoteIdentifier(plan.Name.ValueString())
+
+	// A read-only filegroup must be made writable before its files change.
+	if !plan.ReadOnly.ValueBool() && state.ReadOnly.ValueBool() {
+		diags.Append(r.setReadOnly(ctx, db, plan, false)...)
+		if diags.HasError() {
+			return diags
+		}
+		state.ReadOnly = plan.ReadOnly
+	}
+
+	dbConn, err := r.client.Database(ctx, plan.Database.ValueString())
+	if err != nil {
+		diags.AddError("Unable to connect to database", err.Error())
+		return diags
+	}
+	current := *state
+	if _, err := r.read(ctx, &current); err != nil {
+		diags.AddError("Error reading filegroup", err.Error())
+		return diags
+	}
+	diags.Append(alterDatabaseFiles(ctx, db, dbConn, plan.Database.ValueString(), path.Root("file"), plan.Files, current.Files,
+		func(specs string) string { return "ADD FILE " + specs + " TO FILEGROUP " + filegroup })...)
+	if diags.HasError() {
+		return diags
+	}
+	if _, err := r.read(ctx, &current); err != nil {
+		diags.AddError("Error reading filegroup", err.Error())
+		return diags
+	}
+	state.Files = mergeDatabaseFiles(plan.Files, current.Files)
+
+	if !plan.Default.Equal(state.Default) {
+		target := filegroup
+		if !plan.Default.ValueBool() {
+			target = "[PRIMARY]"
+		}
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY FILEGROUP %s DEFAULT", database, target))
+		if err != nil {
+			diags.AddAttributeError(path.Root("default"), "Error changing default filegroup", err.Error())
+			return diags
+		}
+		state.Default = plan.Default
+	}
+
+	if plan.ReadOnly.ValueBool() && !state.ReadOnly.ValueBool() {
+		diags.Append(r.setReadOnly(ctx, db, plan, true)...)
+		if diags.HasError() {
+			return diags
+		}
+		state.ReadOnly = plan.ReadOnly
+	}
+
+	return diags
+}
+
+// setReadOnly marks the filegroup read-only or writable. SQL Server requires
+// exclusive access to the database, so the provider's own pooled sessions are
+// closed first.
+func (r *filegroupResource) setReadOnly(ctx context.Context, db *sql.DB, data *filegroupResourceModel, readOnly bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	option := "READ_WRITE"
+	if readOnly {
+		option = "READ_ONLY"
+	}
+	r.client.Release(data.Database.ValueString())
+	_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY FILEGROUP %s %s",
+		quoteIdentifier(data.Database.ValueString()), quoteIdentifier(data.Name.ValueString()), option))
+	if err != nil {
+		diags.AddAttributeError(path.Root("read_only"), "Error changing filegroup read-only state", err.Error())
+	}
+	return diags
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *filegroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data filegroupResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	database := quoteIdentifier(data.Database.ValueString())
+
+	if data.Default.ValueBool() {
+		_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s MODIFY FILEGROUP [PRIMARY] DEFAULT", database))
+		if err != nil {
+			resp.Diagnostics.AddError("Error changing default filegroup", err.Error())
+			return
+		}
+	}
+
+	// Files must be empty before they can be removed, the provider does not
+	// move data out of the filegroup.
+	for _, f := range data.Files {
+		_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s REMOVE FILE %s", database, quoteIdentifier(f.Name.ValueString())))
+		if err != nil {
+			resp.Diagnostics.AddError("Error removing filegroup file", fmt.Sprintf("File %q: %s", f.Name.ValueString(), err.Error()))
+			return
+		}
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s REMOVE FILEGROUP %s", database, quoteIdentifier(data.Name.ValueString())))
+	if err != nil {
+		detail := err.Error()
+		if data.fgType() == filegroupTypeMemoryOptimizedData {
+			detail += "\n\nMemory-optimized filegroups can only be removed by dropping the database."
+		}
+		resp.Diagnostics.AddError("Error deleting filegroup", detail)
+		return
+	}
+}
+
+// ImportState imports a filegroup from an ID of the form database/name.
+func (r *filegroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/name")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *filegroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &mssqlLoginDataSource{}
+var _ datasource.DataSourceWithConfigure = &mssqlLoginDataSource{}
+
+func NewMssqlLoginDataSource() datasource.DataSource {
+	return &mssqlLoginDataSource{}
+}
+
+type mssqlLoginDataSource struct {
+	client *mssqlClient
+}
+
+type mssqlLoginDataSourceModel struct {
+	Name            types.String `tfsdk:"name"`
+	Type            types.String `tfsdk:"type"`
+	Sid             types.String `tfsdk:"sid"`
+	PasswordHash    types.String `tfsdk:"password_hash"`
+	DefaultDatabase types.String `tfsdk:"default_database"`
+	DefaultLanguage types.String `tfsdk:"default_language"`
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	CheckPolicy     types.Bool   `tfsdk:"check_policy"`
+	CheckExpiration types.Bool   `tfsdk:"check_expiration"`
+	Id              types.String `tfsdk:"id"`
+}
+
+func (d *mssqlLoginDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_login"
+}
+
+func (d *mssqlLoginDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Login data source.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Login name.",
+				Required:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Login type: sql, windows or external.",
+				Computed:            true,
+			},
+			"sid": schema.StringAttribute{
+				MarkdownDescription: "Security identifier of the login as a binary literal.",
+				Computed:            true,
+			},
+			"password_hash": schema.StringAttribute{
+				MarkdownDescription: "Password hash of a sql login as a binary literal, for `password_hash` of `mssql_login`. " +
+					"Null for other logins. Reading it requires `CONTROL SERVER`.",
+				Computed:  true,
+				Sensitive: true,
+			},
+			"default_database": schema.StringAttribute{
+				MarkdownDescription: "Default database.",
+				Computed:            true,
+			},
+			"default_language": schema.StringAttribute{
+				MarkdownDescription: "Default language.",
+				Computed:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the login can connect.",
+				Computed:            true,
+			},
+			"check_policy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Windows password policy is enforced.",
+				Computed:            true,
+			},
+			"check_expiration": schema.BoolAttribute{
+				MarkdownDescription: "Whether password expiration is enforced.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Login identifier.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *mssqlLoginDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+func (d *mssqlLoginDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data mssqlLoginDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	db, err := d.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+
+	login, err := readLogin(ctx, db, data.Name.ValueString(), types.StringNull())
+	if err == sql.ErrNoRows {
+		resp.Diagnostics.AddError("Login not found", fmt.Sprintf("No login named %q exists on the server.", data.Name.ValueString()))
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error reading login", err.Error())
+		return
+	}
+
+	data.Type = types.StringValue(loginType(login.principalType))
+	data.Sid = types.StringValue(login.sid)
+	data.PasswordHash = types.StringNull()
+	if login.passwordHash.Valid {
+		data.PasswordHash = types.StringValue(login.passwordHash.String)
+	}
+	data.DefaultDatabase = types.StringNull()
+	if login.defaultDatabase.Valid {
+		data.DefaultDatabase = types.StringValue(login.defaultDatabase.String)
+	}
+	data.DefaultLanguage = types.StringNull()
+	if login.defaultLanguage.Valid {
+		data.DefaultLanguage = types.StringValue(login.defaultLanguage.String)
+	}
+	data.Enabled = types.BoolValue(!login.isDisabled)
+	data.CheckPolicy = types.BoolValue(login.checkPolicy.Bool)
+	data.CheckExpiration = types.BoolValue(login.checkExpiration.Bool)
+	data.Id = types.StringValue(login.name)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
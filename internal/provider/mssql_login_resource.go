@@ -7,10 +7,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -19,21 +24,35 @@ import (
 
 var _ resource.Resource = &MssqlLoginResource{}
 var _ resource.ResourceWithImportState = &MssqlLoginResource{}
+var _ resource.ResourceWithValidateConfig = &MssqlLoginResource{}
+
+// binaryPattern matches a binary literal, such as a SID or password hash.
+var binaryPattern = regexp.MustCompile(`^0x[0-9A-Fa-f]+$`)
 
 func NewMssqlLoginResource() resource.Resource {
 	return &MssqlLoginResource{}
 }
 
 type MssqlLoginResource struct {
-	client *sql.DB
+	client *mssqlClient
 }
 
 type MssqlLoginResourceModel struct {
-	Name            types.String `tfsdk:"name"`
-	Password        types.String `tfsdk:"password"`
-	Type            types.String `tfsdk:"type"`
-	DefaultDatabase types.String `tfsdk:"default_database"`
-	Id              types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWo        types.String `tfsdk:"password_wo"`
+	PasswordWoVersion types.Int64  `tfsdk:"password_wo_version"`
+	PasswordHash      types.String `tfsdk:"password_hash"`
+	Type              types.String `tfsdk:"type"`
+	DefaultDatabase   types.String `tfsdk:"default_database"`
+	DefaultLanguage   types.String `tfsdk:"default_language"`
+	Enabled           types.Bool   `tfsdk:"enabled"`
+	CheckPolicy       types.Bool   `tfsdk:"check_policy"`
+	CheckExpiration   types.Bool   `tfsdk:"check_expiration"`
+	MustChange        types.Bool   `tfsdk:"must_change"`
+	KillSessions      types.Bool   `tfsdk:"kill_sessions_on_destroy"`
+	Sid               types.String `tfsdk:"sid"`
+	Id                types.String `tfsdk:"id"`
 }
 
 func (r *MssqlLoginResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -49,13 +68,36 @@ func (r *MssqlLoginResource) Schema(ctx context.Context, req resource.SchemaRequ
 				Required:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Login password.",
-				Required:            true,
-				Sensitive:           true,
+				MarkdownDescription: "Login password. Required for sql logins unless `password_wo` or `password_hash` is set. It is stored in " +
+					"Terraform state; prefer `password_wo`.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"password_wo": schema.StringAttribute{
+				MarkdownDescription: "Write-only login password, which is never stored in Terraform state. It is only applied " +
+					"when the login is created or `password_wo_version` changes. Requires Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"password_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `password_wo`. Change it to apply a new `password_wo`.",
+				Optional:            true,
+			},
+			"password_hash": schema.StringAttribute{
+				MarkdownDescription: "Password hash as a binary literal, as returned by `LOGINPROPERTY(name, 'PasswordHash')` or the " +
+					"`mssql_login` data source (`PASSWORD = ... HASHED`). Use it to copy a sql login between servers without " +
+					"knowing its password. Conflicts with `password` and `password_wo`.",
+				Optional:  true,
+				Sensitive: true,
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "Login type: sql or windows.",
-				Required:            true,
+				MarkdownDescription: "Login type: sql, windows or external, for Microsoft Entra ID logins (`FROM EXTERNAL PROVIDER`). " +
+					"Changing it replaces the login.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"default_database": schema.StringAttribute{
 				MarkdownDescription: "Default database. Defaults to master.",
@@ -63,6 +105,66 @@ func (r *MssqlLoginResource) Schema(ctx context.Context, req resource.SchemaRequ
 				Computed:            true,
 				Default:             stringdefault.StaticString("master"),
 			},
+			"default_language": schema.StringAttribute{
+				MarkdownDescription: "Default language of the login. Defaults to the server's default language.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the login can connect (`ALTER LOGIN ENABLE` or `DISABLE`). When unset, the login is " +
+					"left as it is, which is enabled for new logins.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"check_policy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Windows password policy is enforced (`CHECK_POLICY`). Defaults to true for sql logins. " +
+					"Always false for windows and external logins.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"check_expiration": schema.BoolAttribute{
+				MarkdownDescription: "Whether password expiration is enforced (`CHECK_EXPIRATION`). Requires `check_policy`. " +
+					"Defaults to false.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"must_change": schema.BoolAttribute{
+				MarkdownDescription: "Whether the user must change the password on first use (`MUST_CHANGE`). Applied when the login " +
+					"is created and when the password changes. Requires `check_expiration`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"kill_sessions_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroy disables the login and kills its sessions before dropping it. Without it, " +
+					"`DROP LOGIN` fails while the login is connected.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"sid": schema.StringAttribute{
+				MarkdownDescription: "Security identifier of the login as a binary literal, such as `0x241C11948AEEB749B0D22646DB1A19F2`. " +
+					"Set it to give a sql login the same SID on every server, so users in restored or failed over databases are not " +
+					"orphaned. Changing it replaces the login.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Login identifier.",
@@ -78,11 +180,11 @@ func (r *MssqlLoginResource) Configure(ctx context.Context, req resource.Configu
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*sql.DB)
+	client, ok := req.ProviderData.(*mssqlClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sql.DB, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mssqlClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -90,41 +192,229 @@ func (r *MssqlLoginResource) Configure(ctx context.Context, req resource.Configu
 }
 
 func (r *MssqlLoginResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data MssqlLoginResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...) // Read plan
+	var data, config MssqlLoginResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)     // Read plan
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...) // Write-only values are only in the config
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
 	// Create login in MSSQL
-	loginType := data.Type.ValueString()
-	var createStmt string
-	if loginType == "sql" {
-		createStmt = fmt.Sprintf("CREATE LOGIN [%s] WITH PASSWORD = '%s', DEFAULT_DATABASE = [%s]", data.Name.ValueString(), data.Password.ValueString(), data.DefaultDatabase.ValueString())
-	} else if loginType == "windows" {
-		createStmt = fmt.Sprintf("CREATE LOGIN [%s] FROM WINDOWS WITH DEFAULT_DATABASE = [%s]", data.Name.ValueString(), data.DefaultDatabase.ValueString())
-	} else {
-		resp.Diagnostics.AddError("Invalid login type", "Type must be 'sql' or 'windows'.")
+	login := data
+	if !config.PasswordWo.IsNull() {
+		login.Password = config.PasswordWo
+	}
+	createStmt, err := createLoginStatement(&login)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid login configuration", err.Error())
 		return
 	}
-	_, err := r.client.ExecContext(ctx, createStmt)
+	_, err = db.ExecContext(ctx, createStmt)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating login", err.Error())
 		return
 	}
-	data.Id = types.StringValue(data.Name.ValueString())
+	if known(data.Enabled) && !data.Enabled.ValueBool() {
+		if err := setLoginEnabled(ctx, db, data.Name.ValueString(), false); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("enabled"), "Error disabling login", err.Error())
+			return
+		}
+	}
+	if err := r.read(ctx, db, &data); err != nil {
+		resp.Diagnostics.AddError("Error reading login", err.Error())
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
 }
 
+// createLoginStatement builds the CREATE LOGIN statement for the planned
+// login. Options left unknown in the plan get the server defaults.
+func createLoginStatement(data *MssqlLoginResourceModel) (string, error) {
+	name := quoteIdentifier(data.Name.ValueString())
+	options := []string{}
+	var stmt string
+	switch data.Type.ValueString() {
+	case "sql":
+		stmt = "CREATE LOGIN " + name
+		password, err := loginPasswordOption(data)
+		if err != nil {
+			return "", err
+		}
+		options = append(options, password)
+		if known(data.Sid) {
+			if !binaryPattern.MatchString(data.Sid.ValueString()) {
+				return "", fmt.Errorf("invalid SID %q", data.Sid.ValueString())
+			}
+			options = append(options, "SID = "+data.Sid.ValueString())
+		}
+	case "windows":
+		stmt = "CREATE LOGIN " + name + " FROM WINDOWS"
+	case "external":
+		stmt = "CREATE LOGIN " + name + " FROM EXTERNAL PROVIDER"
+	default:
+		return "", fmt.Errorf("type must be 'sql', 'windows' or 'external'")
+	}
+	// Azure SQL Database takes no options for external logins, so master,
+	// which is the default anyway, is left out for them.
+	if data.Type.ValueString() != "external" || !strings.EqualFold(data.DefaultDatabase.ValueString(), "master") {
+		options = append(options, "DEFAULT_DATABASE = "+quoteIdentifier(data.DefaultDatabase.ValueString()))
+	}
+	if known(data.DefaultLanguage) {
+		options = append(options, "DEFAULT_LANGUAGE = "+quoteIdentifier(data.DefaultLanguage.ValueString()))
+	}
+	if data.Type.ValueString() == "sql" {
+		if known(data.CheckPolicy) {
+			options = append(options, "CHECK_POLICY = "+onOff(data.CheckPolicy.ValueBool()))
+		}
+		if known(data.CheckExpiration) {
+			options = append(options, "CHECK_EXPIRATION = "+onOff(data.CheckExpiration.ValueBool()))
+		}
+	}
+	if len(options) == 0 {
+		return stmt, nil
+	}
+	return stmt + " WITH " + strings.Join(options, ", "), nil
+}
+
+// loginPasswordOption returns the PASSWORD option for a sql login, from
+// either the password or its hash.
+func loginPasswordOption(data *MssqlLoginResourceModel) (string, error) {
+	if known(data.PasswordHash) {
+		if !binaryPattern.MatchString(data.PasswordHash.ValueString()) {
+			return "", fmt.Errorf("invalid password hash")
+		}
+		return "PASSWORD = " + data.PasswordHash.ValueString() + " HASHED", nil
+	}
+	password := "PASSWORD = " + quoteString(data.Password.ValueString())
+	if data.MustChange.ValueBool() {
+		password += " MUST_CHANGE"
+	}
+	return password, nil
+}
+
+// alterLoginOptions returns the ALTER LOGIN options that move the login from
+// state to plan, in the order they must be applied. CHECK_EXPIRATION needs
+// CHECK_POLICY and MUST_CHANGE needs CHECK_EXPIRATION, so the checks are
+// switched on first and off last. The password is only set again when it
+// changed, so must_change is not re-armed by unrelated updates.
+func alterLoginOptions(plan, state *MssqlLoginResourceModel) ([]string, error) {
+	var options []string
+	sqlLogin := plan.Type.ValueString() == "sql"
+	changed := func(planned, actual types.Bool, on bool) bool {
+		return sqlLogin && known(planned) && planned.ValueBool() == on && !planned.Equal(actual)
+	}
+
+	if changed(plan.CheckPolicy, state.CheckPolicy, true) {
+		options = append(options, "CHECK_POLICY = ON")
+	}
+	if changed(plan.CheckExpiration, state.CheckExpiration, true) {
+		options = append(options, "CHECK_EXPIRATION = ON")
+	}
+	if sqlLogin && (!plan.Password.Equal(state.Password) ||
+		(known(plan.PasswordHash) && !strings.EqualFold(plan.PasswordHash.ValueString(), state.PasswordHash.ValueString()))) {
+		password, err := loginPasswordOption(plan)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, password)
+	}
+	if !strings.EqualFold(plan.DefaultDatabase.ValueString(), state.DefaultDatabase.ValueString()) {
+		options = append(options, "DEFAULT_DATABASE = "+quoteIdentifier(plan.DefaultDatabase.ValueString()))
+	}
+	if known(plan.DefaultLanguage) && !strings.EqualFold(plan.DefaultLanguage.ValueString(), state.DefaultLanguage.ValueString()) {
+		options = append(options, "DEFAULT_LANGUAGE = "+quoteIdentifier(plan.DefaultLanguage.ValueString()))
+	}
+	if changed(plan.CheckExpiration, state.CheckExpiration, false) {
+		options = append(options, "CHECK_EXPIRATION = OFF")
+	}
+	if changed(plan.CheckPolicy, state.CheckPolicy, false) {
+		options = append(options, "CHECK_POLICY = OFF")
+	}
+	return options, nil
+}
+
+func (r *MssqlLoginResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data MssqlLoginResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	passwords := 0
+	for _, v := range []types.String{data.Password, data.PasswordWo, data.PasswordHash} {
+		if !v.IsNull() {
+			passwords++
+		}
+	}
+	if data.Type.ValueString() == "sql" && passwords == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing password",
+			"sql logins require one of password, password_wo or password_hash.")
+	}
+	if passwords > 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Conflicting password settings",
+			"Only one of password, password_wo and password_hash can be set.")
+	}
+	if known(data.PasswordHash) && !binaryPattern.MatchString(data.PasswordHash.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("password_hash"), "Invalid password_hash",
+			"password_hash must be a binary literal, such as the value of LOGINPROPERTY(name, 'PasswordHash').")
+	}
+	if !data.PasswordHash.IsNull() && data.MustChange.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("must_change"), "Conflicting password options",
+			"must_change cannot be combined with password_hash.")
+	}
+	if !data.PasswordWo.IsNull() && data.PasswordWoVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo_version"), "Missing password_wo_version",
+			"password_wo requires password_wo_version, which must change for a new password_wo to be applied.")
+	}
+	if known(data.Sid) && !binaryPattern.MatchString(data.Sid.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("sid"), "Invalid SID",
+			"sid must be a binary literal, such as 0x241C11948AEEB749B0D22646DB1A19F2.")
+	}
+	if data.Type.ValueString() == "windows" || data.Type.ValueString() == "external" {
+		for name, v := range map[string]attr.Value{"sid": data.Sid, "password_hash": data.PasswordHash, "check_policy": data.CheckPolicy, "check_expiration": data.CheckExpiration} {
+			if !v.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(name), "Unsupported login option",
+					name+" only applies to sql logins.")
+			}
+		}
+	}
+	if data.MustChange.ValueBool() && !data.CheckExpiration.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("must_change"), "Conflicting password options",
+			"must_change requires check_expiration = true.")
+	}
+	if data.CheckExpiration.ValueBool() && known(data.CheckPolicy) && !data.CheckPolicy.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("check_expiration"), "Conflicting password options",
+			"check_expiration requires check_policy.")
+	}
+}
+
+func setLoginEnabled(ctx context.Context, db *sql.DB, name string, enabled bool) error {
+	action := "DISABLE"
+	if enabled {
+		action = "ENABLE"
+	}
+	_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER LOGIN %s %s", quoteIdentifier(name), action))
+	return err
+}
+
 func (r *MssqlLoginResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data MssqlLoginResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...) // Read state
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Check if login exists
-	row := r.client.QueryRowContext(ctx, "SELECT name FROM sys.server_principals WHERE name = @p1", data.Name.ValueString())
-	var name string
-	err := row.Scan(&name)
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	err = r.read(ctx, db, &data)
 	if err == sql.ErrNoRows {
 		resp.State.RemoveResource(ctx)
 		return
@@ -135,31 +425,161 @@ func (r *MssqlLoginResource) Read(ctx context.Context, req resource.ReadRequest,
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
 }
 
+// read refreshes data from sys.server_principals and sys.sql_logins. An
+// imported login only has its Id, which is the login name. A password that
+// no longer matches the login is cleared so the next plan resets it, and a
+// managed password hash is replaced by the actual one. It returns
+// sql.ErrNoRows if the login does not exist.
+func (r *MssqlLoginResource) read(ctx context.Context, db *sql.DB, data *MssqlLoginResourceModel) error {
+	name := data.Name.ValueString()
+	if data.Name.IsNull() {
+		name = data.Id.ValueString()
+	}
+	login, err := readLogin(ctx, db, name, data.Password)
+	if err != nil {
+		return err
+	}
+
+	data.Name = keepCase(data.Name, login.name)
+	data.Type = types.StringValue(loginType(login.principalType))
+	data.DefaultDatabase = types.StringNull()
+	if login.defaultDatabase.Valid {
+		data.DefaultDatabase = keepCase(data.DefaultDatabase, login.defaultDatabase.String)
+	}
+	data.DefaultLanguage = types.StringNull()
+	if login.defaultLanguage.Valid {
+		data.DefaultLanguage = keepCase(data.DefaultLanguage, login.defaultLanguage.String)
+	}
+	data.Enabled = types.BoolValue(!login.isDisabled)
+	// kill_sessions_on_destroy is not stored on the server.
+	if data.KillSessions.IsNull() {
+		data.KillSessions = types.BoolValue(false)
+	}
+	data.CheckPolicy = types.BoolValue(login.checkPolicy.Bool)
+	data.CheckExpiration = types.BoolValue(login.checkExpiration.Bool)
+	if !strings.EqualFold(data.Sid.ValueString(), login.sid) {
+		data.Sid = types.StringValue(login.sid)
+	}
+	// MUST_CHANGE cannot be read back once the password was changed, so
+	// must_change keeps its configured value.
+	if data.MustChange.IsNull() {
+		data.MustChange = types.BoolValue(false)
+	}
+	if login.passwordMatches.Valid && !login.passwordMatches.Bool {
+		data.Password = types.StringNull()
+	}
+	// password_hash is NULL without CONTROL SERVER, keep the configured hash
+	// then.
+	if !data.PasswordHash.IsNull() && login.passwordHash.Valid && !strings.EqualFold(data.PasswordHash.ValueString(), login.passwordHash.String) {
+		data.PasswordHash = types.StringValue(login.passwordHash.String)
+	}
+	data.Id = types.StringValue(data.Name.ValueString())
+	return nil
+}
+
+// loginProperties is a row of sys.server_principals joined with
+// sys.sql_logins. The sys.sql_logins columns are NULL for other logins.
+type loginProperties struct {
+	name, principalType string
+	defaultDatabase     sql.NullString
+	defaultLanguage     sql.NullString
+	isDisabled          bool
+	checkPolicy         sql.NullBool
+	checkExpiration     sql.NullBool
+	sid                 string
+	passwordHash        sql.NullString
+	// passwordMatches is the PWDCOMPARE result for password, or NULL when
+	// there is no password to compare.
+	passwordMatches sql.NullBool
+}
+
+// readLogin reads the properties of the login name. It returns sql.ErrNoRows
+// if the login does not exist.
+func readLogin(ctx context.Context, db *sql.DB, name string, password types.String) (loginProperties, error) {
+	var login loginProperties
+	err := db.QueryRowContext(ctx, `
+		SELECT p.name, p.type, p.default_database_name, p.default_language_name, p.is_disabled,
+			l.is_policy_checked, l.is_expiration_checked, CONVERT(varchar(172), p.sid, 1),
+			CONVERT(varchar(max), l.password_hash, 1),
+			CASE WHEN @p2 IS NULL OR l.password_hash IS NULL THEN NULL
+				ELSE CAST(PWDCOMPARE(@p2, l.password_hash) AS bit) END
+		FROM sys.server_principals p
+		LEFT JOIN sys.sql_logins l ON l.principal_id = p.principal_id
+		WHERE p.name = @p1 AND p.type IN ('S', 'U', 'G', 'E', 'X')`, name, nullString(password)).
+		Scan(&login.name, &login.principalType, &login.defaultDatabase, &login.defaultLanguage, &login.isDisabled,
+			&login.checkPolicy, &login.checkExpiration, &login.sid, &login.passwordHash, &login.passwordMatches)
+	return login, err
+}
+
+// loginType maps the type column of sys.server_principals to the type
+// attribute.
+func loginType(principalType string) string {
+	switch principalType {
+	case "S":
+		return "sql"
+	case "U", "G":
+		return "windows"
+	case "E", "X":
+		return "external"
+	}
+	return principalType
+}
+
 func (r *MssqlLoginResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan MssqlLoginResourceModel
 	var state MssqlLoginResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)   // Read plan
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...) // Read state
+	var config MssqlLoginResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)     // Read plan
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)   // Read state
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...) // Write-only values are only in the config
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
 	// If name changed, rename login
 	if plan.Name.ValueString() != state.Name.ValueString() {
-		_, err := r.client.ExecContext(ctx, fmt.Sprintf("ALTER LOGIN [%s] WITH NAME = [%s]", state.Name.ValueString(), plan.Name.ValueString()))
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER LOGIN %s WITH NAME = %s", quoteIdentifier(state.Name.ValueString()), quoteIdentifier(plan.Name.ValueString())))
 		if err != nil {
 			resp.Diagnostics.AddError("Error renaming login", err.Error())
 			return
 		}
 		state.Name = plan.Name
 	}
-	// Update password and default_database if type is sql
-	if plan.Type.ValueString() == "sql" {
-		_, err := r.client.ExecContext(ctx, fmt.Sprintf("ALTER LOGIN [%s] WITH PASSWORD = '%s', DEFAULT_DATABASE = [%s]", plan.Name.ValueString(), plan.Password.ValueString(), plan.DefaultDatabase.ValueString()))
+	// Update password, defaults and policy in place, one option per
+	// statement so each check is in effect before the next one needs it.
+	// A write-only password is applied as if it changed whenever its version
+	// does.
+	login := plan
+	if !config.PasswordWo.IsNull() && !plan.PasswordWoVersion.Equal(state.PasswordWoVersion) {
+		login.Password = config.PasswordWo
+	}
+	options, err := alterLoginOptions(&login, &state)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("password_hash"), "Invalid login configuration", err.Error())
+		return
+	}
+	for _, option := range options {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER LOGIN %s WITH %s", quoteIdentifier(plan.Name.ValueString()), option))
 		if err != nil {
 			resp.Diagnostics.AddError("Error updating login", err.Error())
 			return
 		}
 	}
+	if known(plan.Enabled) && !plan.Enabled.Equal(state.Enabled) {
+		if err := setLoginEnabled(ctx, db, plan.Name.ValueString(), plan.Enabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("enabled"), "Error changing login state", err.Error())
+			return
+		}
+	}
+	if err := r.read(ctx, db, &plan); err != nil {
+		resp.Diagnostics.AddError("Error reading login", err.Error())
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
 }
 
@@ -169,13 +589,63 @@ func (r *MssqlLoginResource) Delete(ctx context.Context, req resource.DeleteRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf("DROP LOGIN [%s]", data.Name.ValueString()))
+
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to SQL Server", err.Error())
+		return
+	}
+	if data.KillSessions.ValueBool() {
+		if err := killLoginSessions(ctx, db, data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Error killing login sessions", err.Error())
+			return
+		}
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP LOGIN %s", quoteIdentifier(data.Name.ValueString())))
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting login", err.Error())
+		detail := err.Error()
+		if hasSQLError(err, 15434) {
+			detail += "\n\nThe login is still connected. Set kill_sessions_on_destroy = true to kill its sessions before it is dropped."
+		}
+		resp.Diagnostics.AddError("Error deleting login", detail)
 		return
 	}
 }
 
+// killLoginSessions disables the login, so it cannot reconnect, and kills
+// every session it has open apart from the provider's own.
+func killLoginSessions(ctx context.Context, db *sql.DB, name string) error {
+	if err := setLoginEnabled(ctx, db, name, false); err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT session_id FROM sys.dm_exec_sessions WHERE login_name = @p1 AND session_id <> @@SPID", name)
+	if err != nil {
+		return err
+	}
+	var sessions []int16
+	for rows.Next() {
+		var id int16
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		sessions = append(sessions, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range sessions {
+		// 6106: the session ended on its own in the meantime.
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("KILL %d", id)); err != nil && !hasSQLError(err, 6106) {
+			return fmt.Errorf("killing session %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
 func (r *MssqlLoginResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
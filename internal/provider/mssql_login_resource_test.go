@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLoginType(t *testing.T) {
+	cases := map[string]string{
+		"S": "sql",
+		"U": "windows",
+		"G": "windows",
+		"E": "external",
+		"X": "external",
+		"C": "C",
+	}
+	for principalType, want := range cases {
+		if got := loginType(principalType); got != want {
+			t.Errorf("loginType(%q) = %q, want %q", principalType, got, want)
+		}
+	}
+}
+
+func TestCreateLoginStatement(t *testing.T) {
+	cases := []struct {
+		data MssqlLoginResourceModel
+		want string
+	}{
+		{
+			MssqlLoginResourceModel{
+				Name:            types.StringValue("app"),
+				Password:        types.StringValue("it's secret"),
+				Type:            types.StringValue("sql"),
+				DefaultDatabase: types.StringValue("master"),
+				DefaultLanguage: types.StringUnknown(),
+				CheckPolicy:     types.BoolUnknown(),
+				CheckExpiration: types.BoolUnknown(),
+				MustChange:      types.BoolValue(false),
+				Sid:             types.StringUnknown(),
+			},
+			"CREATE LOGIN [app] WITH PASSWORD = N'it''s secret', DEFAULT_DATABASE = [master]",
+		},
+		{
+			MssqlLoginResourceModel{
+				Name:            types.StringValue("app"),
+				Password:        types.StringValue("secret"),
+				Type:            types.StringValue("sql"),
+				DefaultDatabase: types.StringValue("app"),
+				DefaultLanguage: types.StringValue("us_english"),
+				CheckPolicy:     types.BoolValue(true),
+				CheckExpiration: types.BoolValue(true),
+				MustChange:      types.BoolValue(true),
+				Sid:             types.StringValue("0x241C11948AEEB749B0D22646DB1A19F2"),
+			},
+			"CREATE LOGIN [app] WITH PASSWORD = N'secret' MUST_CHANGE, SID = 0x241C11948AEEB749B0D22646DB1A19F2, " +
+				"DEFAULT_DATABASE = [app], DEFAULT_LANGUAGE = [us_english], CHECK_POLICY = ON, CHECK_EXPIRATION = ON",
+		},
+		{
+			MssqlLoginResourceModel{
+				Name:            types.StringValue(`DOMAIN\app`),
+				Type:            types.StringValue("windows"),
+				DefaultDatabase: types.StringValue("master"),
+				DefaultLanguage: types.StringUnknown(),
+				CheckPolicy:     types.BoolUnknown(),
+				CheckExpiration: types.BoolUnknown(),
+			},
+			`CREATE LOGIN [DOMAIN\app] FROM WINDOWS WITH DEFAULT_DATABASE = [master]`,
+		},
+		{
+			MssqlLoginResourceModel{
+				Name:            types.StringValue("app@contoso.com"),
+				Type:            types.StringValue("external"),
+				DefaultDatabase: types.StringValue("master"),
+				DefaultLanguage: types.StringUnknown(),
+				CheckPolicy:     types.BoolUnknown(),
+				CheckExpiration: types.BoolUnknown(),
+			},
+			"CREATE LOGIN [app@contoso.com] FROM EXTERNAL PROVIDER",
+		},
+		{
+			MssqlLoginResourceModel{
+				Name:            types.StringValue("app@contoso.com"),
+				Type:            types.StringValue("external"),
+				DefaultDatabase: types.StringValue("app"),
+				DefaultLanguage: types.StringValue("us_english"),
+			},
+			"CREATE LOGIN [app@contoso.com] FROM EXTERNAL PROVIDER WITH DEFAULT_DATABASE = [app], DEFAULT_LANGUAGE = [us_english]",
+		},
+		{
+			MssqlLoginResourceModel{
+				Name:            types.StringValue("app"),
+				PasswordHash:    types.StringValue("0x0200A1B2C3D4"),
+				Type:            types.StringValue("sql"),
+				DefaultDatabase: types.StringValue("master"),
+				DefaultLanguage: types.StringUnknown(),
+				CheckPolicy:     types.BoolValue(false),
+				CheckExpiration: types.BoolUnknown(),
+				MustChange:      types.BoolValue(false),
+				Sid:             types.StringValue("0x241C11948AEEB749B0D22646DB1A19F2"),
+			},
+			"CREATE LOGIN [app] WITH PASSWORD = 0x0200A1B2C3D4 HASHED, SID = 0x241C11948AEEB749B0D22646DB1A19F2, " +
+				"DEFAULT_DATABASE = [master], CHECK_POLICY = OFF",
+		},
+	}
+	for _, tc := range cases {
+		got, err := createLoginStatement(&tc.data)
+		if err != nil {
+			t.Errorf("createLoginStatement() unexpected error: %s", err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("createLoginStatement() = %q, want %q", got, tc.want)
+		}
+	}
+
+	_, err := createLoginStatement(&MssqlLoginResourceModel{Type: types.StringValue("sql"), Sid: types.StringValue("0x12; DROP LOGIN sa")})
+	if err == nil {
+		t.Error("createLoginStatement() accepted an invalid SID")
+	}
+	_, err = createLoginStatement(&MssqlLoginResourceModel{Type: types.StringValue("sql"), PasswordHash: types.StringValue("0x12 HASHED; DROP LOGIN sa")})
+	if err == nil {
+		t.Error("createLoginStatement() accepted an invalid password hash")
+	}
+}
+
+func TestAlterLoginOptions(t *testing.T) {
+	state := MssqlLoginResourceModel{
+		Password:        types.StringValue("old"),
+		Type:            types.StringValue("sql"),
+		DefaultDatabase: types.StringValue("master"),
+		DefaultLanguage: types.StringValue("us_english"),
+		CheckPolicy:     types.BoolValue(false),
+		CheckExpiration: types.BoolValue(false),
+		MustChange:      types.BoolValue(false),
+	}
+
+	if got, _ := alterLoginOptions(&state, &state); len(got) != 0 {
+		t.Errorf("alterLoginOptions() = %q for an unchanged login", got)
+	}
+
+	plan := state
+	plan.Password = types.StringValue("new")
+	plan.CheckPolicy = types.BoolValue(true)
+	plan.CheckExpiration = types.BoolValue(true)
+	plan.MustChange = types.BoolValue(true)
+	plan.DefaultLanguage = types.StringValue("Deutsch")
+	want := []string{"CHECK_POLICY = ON", "CHECK_EXPIRATION = ON", "PASSWORD = N'new' MUST_CHANGE", "DEFAULT_LANGUAGE = [Deutsch]"}
+	if got, _ := alterLoginOptions(&plan, &state); !reflect.DeepEqual(got, want) {
+		t.Errorf("alterLoginOptions() = %q, want %q", got, want)
+	}
+
+	want = []string{"PASSWORD = N'old'", "DEFAULT_LANGUAGE = [us_english]", "CHECK_EXPIRATION = OFF", "CHECK_POLICY = OFF"}
+	if got, _ := alterLoginOptions(&state, &plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("alterLoginOptions() = %q, want %q", got, want)
+	}
+
+	plan = state
+	state.PasswordHash = types.StringValue("0x0200AAAA")
+	plan.PasswordHash = types.StringValue("0x0200BBBB")
+	want = []string{"PASSWORD = 0x0200BBBB HASHED"}
+	if got, _ := alterLoginOptions(&plan, &state); !reflect.DeepEqual(got, want) {
+		t.Errorf("alterLoginOptions() = %q, want %q", got, want)
+	}
+	plan.PasswordHash = types.StringValue("0x0200aaaa")
+	if got, _ := alterLoginOptions(&plan, &state); len(got) != 0 {
+		t.Errorf("alterLoginOptions() = %q for a hash that differs only in case", got)
+	}
+}
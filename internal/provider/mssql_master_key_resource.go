@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &masterKeyResource{}
+	_ resource.ResourceWithConfigure   = &masterKeyResource{}
+	_ resource.ResourceWithImportState = &masterKeyResource{}
+)
+
+// masterKeyId is the symmetric_key_id of the database master key in
+// sys.symmetric_keys.
+const masterKeyId = 101
+
+// NewMssqlMasterKeyResource a helper function to simplify the provider implementation.
+func NewMssqlMasterKeyResource() resource.Resource {
+	return &masterKeyResource{}
+}
+
+// maps to resource schema table
+type masterKeyResourceModel struct {
+	Database types.String `tfsdk:"database"`
+	Password types.String `tfsdk:"password"`
+	Id       types.String `tfsdk:"id"`
+}
+
+// masterKeyResource is the resource implementation.
+type masterKeyResource struct {
+	client *mssqlClient
+}
+
+// Metadata returns the resource type name.
+func (r *masterKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_master_key"
+}
+
+// Schema defines the schema for the resource.
+func (r *masterKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MSSQL Database master key resource",
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database the master key is created in. Defaults to `master`, which is where the " +
+					"certificates protecting database encryption keys live.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("master"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password that encrypts the master key. Changing it re-encrypts the key with the new password " +
+					"without regenerating it.",
+				Required:  true,
+				Sensitive: true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Master key identifier, the database name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *masterKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data masterKeyResourceModel
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE MASTER KEY ENCRYPTION BY PASSWORD = %s", quoteString(data.Password.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating master key", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(data.Database.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
+}
+
+// Read refreshes the Terraform state with the latest data. The password
+// cannot be read back.
+func (r *masterKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state masterKeyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	var id int
+	err = db.QueryRowContext(ctx, "SELECT symmetric_key_id FROM sys.symmetric_keys WHERE symmetric_key_id = @p1", masterKeyId).Scan(&id)
+	if err == sql.ErrNoRows {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error reading master key", err.Error())
+		return
+	}
+
+	state.Id = types.StringValue(state.Database.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *masterKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state masterKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...) // Read plan
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Password.Equal(state.Password) {
+		// Connect to the target database
+		db, err := r.client.Database(ctx, plan.Database.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+			return
+		}
+		// Adding the new password before dropping the old one keeps the key
+		// openable if the second statement fails. An imported key has no
+		// known password, so there is nothing to drop.
+		stmt := fmt.Sprintf("ALTER MASTER KEY ADD ENCRYPTION BY PASSWORD = %s", quoteString(plan.Password.ValueString()))
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("password"), "Error changing master key password", err.Error())
+			return
+		}
+		if !state.Password.IsNull() {
+			stmt = fmt.Sprintf("ALTER MASTER KEY DROP ENCRYPTION BY PASSWORD = %s", quoteString(state.Password.ValueString()))
+			if _, err := db.ExecContext(ctx, stmt); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("password"), "Error changing master key password", err.Error())
+				return
+			}
+		}
+	}
+
+	plan.Id = types.StringValue(plan.Database.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...) // Save state
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *masterKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data masterKeyResourceModel
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	if _, err := db.ExecContext(ctx, "DROP MASTER KEY"); err != nil {
+		resp.Diagnostics.AddError("Error dropping master key", err.Error())
+		return
+	}
+}
+
+// ImportState imports the master key of the database named by the ID. The
+// password is not stored on the server and must be set in the configuration.
+func (r *masterKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *masterKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*mssqlClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
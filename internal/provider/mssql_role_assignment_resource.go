@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,8 +16,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &roleAssignmentResource{}
-	_ resource.ResourceWithConfigure = &roleAssignmentResource{}
+	_ resource.Resource                = &roleAssignmentResource{}
+	_ resource.ResourceWithConfigure   = &roleAssignmentResource{}
+	_ resource.ResourceWithImportState = &roleAssignmentResource{}
 )
 
 // NewRoleResource a helper function to simplify the provider implementation.
@@ -34,7 +36,7 @@ type roleAssignmentResourceModel struct {
 
 // roleResource is the resource implementation.
 type roleAssignmentResource struct {
-	client *sql.DB
+	client *mssqlClient
 }
 
 // Metadata returns the resource type name.
@@ -61,7 +63,7 @@ func (r *roleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequ
 			},
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Role identifier.",
+				MarkdownDescription: "Role assignment identifier, in the form `database/role_name/member_name`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -78,24 +80,28 @@ func (r *roleAssignmentResource) Create(ctx context.Context, req resource.Create
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Create database in MSSQL
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	// Assign role in MSSQL
 	role := data.RoleName.ValueString()
 	member := data.MemberName.ValueString()
-	database := data.Database.ValueString()
 
 	// Create Statement
 	createStmt := fmt.Sprintf(`
-						-- Switch Context to the db
-						Use [%s];
-						-- Create custom role
-						ALTER ROLE [%s] ADD MEMBER [%s];
-	`, database, role, member)
-	_, err := r.client.ExecContext(ctx, createStmt)
+						-- Add member to the role
+						ALTER ROLE %s ADD MEMBER %s;
+	`, quoteIdentifier(role), quoteIdentifier(member))
+	_, err = db.ExecContext(ctx, createStmt)
 	if err != nil {
 		resp.Diagnostics.AddError("Error assigning role", err.Error())
 		return
 	}
-	data.Id = types.StringValue(data.RoleName.ValueString())
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.RoleName.ValueString(), data.MemberName.ValueString()))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
 }
 
@@ -107,18 +113,23 @@ func (r *roleAssignmentResource) Read(ctx context.Context, req resource.ReadRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	query := fmt.Sprintf(`
-		USE [%s];
-		
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	query := `
 		SELECT dp2.name AS member_name
 		FROM sys.database_role_members drm
 		JOIN sys.database_principals dp1 ON drm.role_principal_id = dp1.principal_id
 		JOIN sys.database_principals dp2 ON drm.member_principal_id = dp2.principal_id
 		WHERE dp1.name = @p1 AND dp2.name = @p2;
-	`, state.Database.ValueString())
-	row := r.client.QueryRowContext(ctx, query, state.RoleName.ValueString(), state.MemberName.ValueString())
+	`
+	row := db.QueryRowContext(ctx, query, state.RoleName.ValueString(), state.MemberName.ValueString())
 	var name string
-	err := row.Scan(&name)
+	err = row.Scan(&name)
 	if err == sql.ErrNoRows {
 		resp.State.RemoveResource(ctx)
 		return
@@ -126,6 +137,7 @@ func (r *roleAssignmentResource) Read(ctx context.Context, req resource.ReadRequ
 		resp.Diagnostics.AddError("Error reading role assignment", err.Error())
 		return
 	}
+	state.Id = types.StringValue(joinId(state.Database.ValueString(), state.RoleName.ValueString(), state.MemberName.ValueString()))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -142,12 +154,17 @@ func (r *roleAssignmentResource) Delete(ctx context.Context, req resource.Delete
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf(`
-		USE [%s];
-		ALTER ROLE [%s] DROP MEMBER [%s];`,
-		data.Database.ValueString(),
-		data.RoleName.ValueString(),
-		data.MemberName.ValueString()))
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf(`
+		ALTER ROLE %s DROP MEMBER %s;`,
+		quoteIdentifier(data.RoleName.ValueString()),
+		quoteIdentifier(data.MemberName.ValueString())))
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting assigned role", err.Error())
 		return
@@ -155,15 +172,29 @@ func (r *roleAssignmentResource) Delete(ctx context.Context, req resource.Delete
 
 }
 
+// ImportState imports a role membership from an ID of the form
+// database/role_name/member_name.
+func (r *roleAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/role_name/member_name")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role_name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("member_name"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
 func (r *roleAssignmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*sql.DB)
+	client, ok := req.ProviderData.(*mssqlClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sql.DB, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -15,8 +16,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &roleResource{}
-	_ resource.ResourceWithConfigure = &roleResource{}
+	_ resource.Resource                = &roleResource{}
+	_ resource.ResourceWithConfigure   = &roleResource{}
+	_ resource.ResourceWithImportState = &roleResource{}
 )
 
 // NewRoleResource a helper function to simplify the provider implementation.
@@ -33,7 +35,7 @@ type roleResourceModel struct {
 
 // roleResource is the resource implementation.
 type roleResource struct {
-	client *sql.DB
+	client *mssqlClient
 }
 
 // Metadata returns the resource type name.
@@ -56,7 +58,7 @@ func (r *roleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			},
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Role identifier.",
+				MarkdownDescription: "Role identifier, in the form `database/name`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -73,23 +75,27 @@ func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Create database in MSSQL
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	// Create role in MSSQL
 	role := data.Name.ValueString()
-	database := data.Database.ValueString()
 
 	// Create Statement
 	createStmt := fmt.Sprintf(`
-						-- Switch Context to the db
-						Use [%s];
 						-- Create custom role
-						CREATE ROLE [%s];
-	`, database, role)
-	_, err := r.client.ExecContext(ctx, createStmt)
+						CREATE ROLE %s;
+	`, quoteIdentifier(role))
+	_, err = db.ExecContext(ctx, createStmt)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating role", err.Error())
 		return
 	}
-	data.Id = types.StringValue(data.Name.ValueString())
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Name.ValueString()))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save state
 }
 
@@ -102,13 +108,19 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	query := fmt.Sprintf(`
-			USE [%s];
-			SELECT 1 FROM sys.database_principals WHERE type = 'R' AND name='%s';
-	`, state.Database.ValueString(), state.Name.ValueString())
-	row := r.client.QueryRowContext(ctx, query)
+	// Connect to the target database
+	db, err := r.client.Database(ctx, state.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+
+	query := `
+			SELECT 1 FROM sys.database_principals WHERE type = 'R' AND name = @p1;
+	`
+	row := db.QueryRowContext(ctx, query, state.Name.ValueString())
 	var name string
-	err := row.Scan(&name)
+	err = row.Scan(&name)
 	if err == sql.ErrNoRows {
 		resp.State.RemoveResource(ctx)
 		return
@@ -116,6 +128,7 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		resp.Diagnostics.AddError("Error reading roles", err.Error())
 		return
 	}
+	state.Id = types.StringValue(joinId(state.Database.ValueString(), state.Name.ValueString()))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -132,7 +145,14 @@ func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf("USE [%s];DROP ROLE [%s]", data.Database.ValueString(), data.Name.ValueString()))
+
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP ROLE %s", quoteIdentifier(data.Name.ValueString())))
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting role", err.Error())
 		return
@@ -140,15 +160,27 @@ func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 }
 
+// ImportState imports a role from an ID of the form database/name.
+func (r *roleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := splitId(req.ID, "database/name")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
 func (r *roleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*sql.DB)
+	client, ok := req.ProviderData.(*mssqlClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sql.DB, got :%T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mssqlClient, got :%T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
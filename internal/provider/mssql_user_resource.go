@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,20 +19,26 @@ import (
 
 var _ resource.Resource = &MssqlUserResource{}
 var _ resource.ResourceWithImportState = &MssqlUserResource{}
+var _ resource.ResourceWithModifyPlan = &MssqlUserResource{}
+var _ resource.ResourceWithValidateConfig = &MssqlUserResource{}
 
 func NewMssqlUserResource() resource.Resource {
 	return &MssqlUserResource{}
 }
 
 type MssqlUserResource struct {
-	client *sql.DB
+	client *mssqlClient
 }
 
 type MssqlUserResourceModel struct {
-	Name     types.String `tfsdk:"name"`
-	Database types.String `tfsdk:"database"`
-	Login    types.String `tfsdk:"login"`
-	Id       types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	Database          types.String `tfsdk:"database"`
+	Login             types.String `tfsdk:"login"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWo        types.String `tfsdk:"password_wo"`
+	PasswordWoVersion types.Int64  `tfsdk:"password_wo_version"`
+	DefaultSchema     types.String `tfsdk:"default_schema"`
+	Id                types.String `tfsdk:"id"`
 }
 
 func (r *MssqlUserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -51,12 +58,38 @@ func (r *MssqlUserResource) Schema(ctx context.Context, req resource.SchemaReque
 				Required:            true,
 			},
 			"login": schema.StringAttribute{
-				MarkdownDescription: "Login name to map the user to.",
+				MarkdownDescription: "Login name to map the user to. Mapping a user to a login, or removing its login, replaces the user.",
 				Optional:            true,
 			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password of a contained database user. Requires a database with `PARTIAL` containment, " +
+					"or Azure SQL Database. Conflicts with `login`.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"password_wo": schema.StringAttribute{
+				MarkdownDescription: "Write-only password of a contained database user, which is never stored in Terraform state. " +
+					"It is only applied when the user is created or `password_wo_version` changes. Conflicts with `password` " +
+					"and `login`. Requires Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"password_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `password_wo`. Change it to apply a new `password_wo`.",
+				Optional:            true,
+			},
+			"default_schema": schema.StringAttribute{
+				MarkdownDescription: "Default schema of the user. Defaults to `dbo`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "User identifier.",
+				MarkdownDescription: "User identifier, in the form `database/name`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -69,11 +102,11 @@ func (r *MssqlUserResource) Configure(ctx context.Context, req resource.Configur
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*sql.DB)
+	client, ok := req.ProviderData.(*mssqlClient)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sql.DB, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mssqlClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -81,37 +114,65 @@ func (r *MssqlUserResource) Configure(ctx context.Context, req resource.Configur
 }
 
 func (r *MssqlUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data MssqlUserResourceModel
+	var data, config MssqlUserResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...) // Write-only values are only in the config
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Switch to the target database
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf("USE [%s]", data.Database.ValueString()))
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error switching to database", err.Error())
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
 		return
 	}
 
 	// Create user in MSSQL
-	var createStmt string
-	if !data.Login.IsNull() && data.Login.ValueString() != "" {
-		createStmt = fmt.Sprintf("USE [%s];CREATE USER [%s] FOR LOGIN [%s]", data.Database.ValueString(), data.Name.ValueString(), data.Login.ValueString())
-	} else {
-		createStmt = fmt.Sprintf("Use [%s];CREATE USER [%s] WITHOUT LOGIN", data.Database.ValueString(), data.Name.ValueString())
+	user := data
+	if !config.PasswordWo.IsNull() {
+		user.Password = config.PasswordWo
 	}
-
-	_, err = r.client.ExecContext(ctx, createStmt)
+	_, err = db.ExecContext(ctx, createUserStatement(&user))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating user", err.Error())
 		return
 	}
 
-	data.Id = types.StringValue(fmt.Sprintf("%s.%s", data.Database.ValueString(), data.Name.ValueString()))
+	if err := r.read(ctx, db, &data); err != nil {
+		resp.Diagnostics.AddError("Error reading user", err.Error())
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// mappedToLogin reports whether login names a login. An empty login is a
+// user without one.
+func mappedToLogin(login types.String) bool {
+	return known(login) && login.ValueString() != ""
+}
+
+// createUserStatement builds the CREATE USER statement. A user is mapped to a
+// login, authenticated by the database with a password, or has no login.
+func createUserStatement(data *MssqlUserResourceModel) string {
+	var options []string
+	stmt := "CREATE USER " + quoteIdentifier(data.Name.ValueString())
+	if mappedToLogin(data.Login) {
+		stmt += " FOR LOGIN " + quoteIdentifier(data.Login.ValueString())
+	} else if !data.Password.IsNull() {
+		options = append(options, "PASSWORD = "+quoteString(data.Password.ValueString()))
+	} else {
+		stmt += " WITHOUT LOGIN"
+	}
+	if known(data.DefaultSchema) {
+		options = append(options, "DEFAULT_SCHEMA = "+quoteIdentifier(data.DefaultSchema.ValueString()))
+	}
+	if len(options) > 0 {
+		stmt += " WITH " + strings.Join(options, ", ")
+	}
+	return stmt
+}
+
 func (r *MssqlUserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data MssqlUserResourceModel
 
@@ -121,19 +182,14 @@ func (r *MssqlUserResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Build the combined SQL query (USE + SELECT)
-	query := fmt.Sprintf(`
-		USE [%s];
-		SELECT name 
-		FROM sys.database_principals 
-		WHERE name = @p1 AND type = 'S';`, data.Database.ValueString())
-
-	// Run the query
-	row := r.client.QueryRowContext(ctx, query, data.Name.ValueString())
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
+		return
+	}
 
-	// Read the result
-	var name string
-	err := row.Scan(&name)
+	err = r.read(ctx, db, &data)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// User doesn't exist — remove from state
@@ -145,38 +201,177 @@ func (r *MssqlUserResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Update state (no change needed if user exists)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// read refreshes data from sys.database_principals. The password cannot be
+// read back. It returns sql.ErrNoRows if the user does not exist.
+func (r *MssqlUserResource) read(ctx context.Context, db *sql.DB, data *MssqlUserResourceModel) error {
+	row := db.QueryRowContext(ctx, `
+		SELECT name, default_schema_name, SUSER_SNAME(sid)
+		FROM sys.database_principals
+		WHERE name = @p1 AND type = 'S';`, data.Name.ValueString())
+
+	var (
+		name          string
+		defaultSchema sql.NullString
+		login         sql.NullString
+	)
+	if err := row.Scan(&name, &defaultSchema, &login); err != nil {
+		return err
+	}
+
+	data.Name = keepCase(data.Name, name)
+	data.DefaultSchema = types.StringNull()
+	if defaultSchema.Valid {
+		data.DefaultSchema = keepCase(data.DefaultSchema, defaultSchema.String)
+	}
+	// Users without a login get a random SID that maps to no login.
+	if login.Valid {
+		data.Login = keepCase(data.Login, login.String)
+	} else if !data.Login.IsNull() {
+		data.Login = types.StringValue("")
+	}
+	data.Id = types.StringValue(joinId(data.Database.ValueString(), data.Name.ValueString()))
+	return nil
+}
+
 func (r *MssqlUserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan MssqlUserResourceModel
 	var state MssqlUserResourceModel
+	var config MssqlUserResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Switch to the target database
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf("USE [%s]", plan.Database.ValueString()))
+	// Connect to the target database
+	db, err := r.client.Database(ctx, plan.Database.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error switching to database", err.Error())
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
 		return
 	}
 
 	// If name changed, rename user
 	if plan.Name.ValueString() != state.Name.ValueString() {
-		_, err := r.client.ExecContext(ctx, fmt.Sprintf("ALTER USER [%s] WITH NAME = [%s]", state.Name.ValueString(), plan.Name.ValueString()))
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER USER %s WITH NAME = %s", quoteIdentifier(state.Name.ValueString()), quoteIdentifier(plan.Name.ValueString())))
 		if err != nil {
 			resp.Diagnostics.AddError("Error renaming user", err.Error())
 			return
 		}
 	}
 
+	var options []string
+	if mappedToLogin(plan.Login) && !strings.EqualFold(plan.Login.ValueString(), state.Login.ValueString()) {
+		options = append(options, "LOGIN = "+quoteIdentifier(plan.Login.ValueString()))
+	}
+	if !plan.Password.IsNull() && !plan.Password.Equal(state.Password) {
+		options = append(options, "PASSWORD = "+quoteString(plan.Password.ValueString()))
+	} else if !config.PasswordWo.IsNull() && !plan.PasswordWoVersion.Equal(state.PasswordWoVersion) {
+		options = append(options, "PASSWORD = "+quoteString(config.PasswordWo.ValueString()))
+	}
+	if known(plan.DefaultSchema) && !strings.EqualFold(plan.DefaultSchema.ValueString(), state.DefaultSchema.ValueString()) {
+		options = append(options, "DEFAULT_SCHEMA = "+quoteIdentifier(plan.DefaultSchema.ValueString()))
+	}
+	if len(options) > 0 {
+		_, err := db.ExecContext(ctx, fmt.Sprintf("ALTER USER %s WITH %s", quoteIdentifier(plan.Name.ValueString()), strings.Join(options, ", ")))
+		if err != nil {
+			resp.Diagnostics.AddError("Error altering user", err.Error())
+			return
+		}
+	}
+
+	if err := r.read(ctx, db, &plan); err != nil {
+		resp.Diagnostics.AddError("Error reading user", err.Error())
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+func (r *MssqlUserResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data MssqlUserResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	withPassword := !data.Password.IsNull() || !data.PasswordWo.IsNull()
+	if withPassword && !data.Login.IsNull() && data.Login.ValueString() != "" {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Conflicting user settings",
+			"A user is either mapped to a login or authenticated by the database with a password, not both.")
+	}
+	if !data.Password.IsNull() && !data.PasswordWo.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo"), "Conflicting password settings",
+			"Only one of password and password_wo can be set.")
+	}
+	if !data.PasswordWo.IsNull() && data.PasswordWoVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo_version"), "Missing password_wo_version",
+			"password_wo requires password_wo_version, which must change for a new password_wo to be applied.")
+	}
+}
+
+// ModifyPlan replaces a user that is mapped to a login or unmapped from
+// one, and warns about a new contained user whose database does not allow
+// them yet. Azure SQL Database always does.
+func (r *MssqlUserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// ALTER USER only moves a user between logins, it cannot turn a user
+	// without a login into one with a login or back.
+	if !req.State.Raw.IsNull() {
+		var plan, state MssqlUserResourceModel
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !plan.Login.IsUnknown() && mappedToLogin(plan.Login) != mappedToLogin(state.Login) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("login"))
+		}
+		// The ID is derived from the database and name.
+		if !plan.Name.Equal(state.Name) || !plan.Database.Equal(state.Database) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+		}
+	}
+
+	// Only a new user is checked. The database may be made contained in the
+	// same apply, so an uncontained one is a warning rather than an error.
+	if !req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+	// The config holds the write-only password, which the plan does not.
+	var config MssqlUserResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || (config.Password.IsNull() && config.PasswordWo.IsNull()) || !known(config.Database) {
+		return
+	}
+
+	// The server is unreachable during plan, let apply report it.
+	edition, err := r.client.EngineEdition(ctx)
+	if err != nil || edition == engineEditionAzureSQLDatabase {
+		return
+	}
+	db, err := r.client.DB(ctx)
+	if err != nil {
+		return
+	}
+	var containment string
+	err = db.QueryRowContext(ctx, "SELECT containment_desc FROM sys.databases WHERE name = @p1", config.Database.ValueString()).Scan(&containment)
+	if err != nil {
+		// The database may be created in the same apply.
+		return
+	}
+	if containment == "NONE" {
+		resp.Diagnostics.AddAttributeWarning(path.Root("password"), "Database is not contained",
+			fmt.Sprintf("Users with a password can only be created in contained databases, but the containment of database %q is NONE. "+
+				"Creating the user fails unless containment = \"PARTIAL\" is set on the database before it is created.", config.Database.ValueString()))
+	}
+}
+
 func (r *MssqlUserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data MssqlUserResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -184,20 +379,28 @@ func (r *MssqlUserResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	// Switch to the target database
-	_, err := r.client.ExecContext(ctx, fmt.Sprintf("USE [%s]", data.Database.ValueString()))
+	// Connect to the target database
+	db, err := r.client.Database(ctx, data.Database.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error switching to database", err.Error())
+		resp.Diagnostics.AddError("Unable to connect to database", err.Error())
 		return
 	}
 
-	_, err = r.client.ExecContext(ctx, fmt.Sprintf("USE [%s]; DROP USER [%s]", data.Database.ValueString(), data.Name.ValueString()))
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP USER %s", quoteIdentifier(data.Name.ValueString())))
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting user", err.Error())
 		return
 	}
 }
 
+// ImportState imports a user from an ID of the form database/name.
 func (r *MssqlUserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	parts, err := splitId(req.ID, "database/name")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -51,4 +52,29 @@ resource "mssql_user" "test" {
   login    = %[3]q
 }
 `, name, database, login)
-}
\ No newline at end of file
+}
+
+func TestCreateUserStatement(t *testing.T) {
+	cases := []struct {
+		data MssqlUserResourceModel
+		want string
+	}{
+		{
+			MssqlUserResourceModel{Name: types.StringValue("app"), Login: types.StringValue("app_login"), DefaultSchema: types.StringUnknown()},
+			"CREATE USER [app] FOR LOGIN [app_login]",
+		},
+		{
+			MssqlUserResourceModel{Name: types.StringValue("app"), Password: types.StringValue("it's secret"), DefaultSchema: types.StringValue("sales")},
+			"CREATE USER [app] WITH PASSWORD = N'it''s secret', DEFAULT_SCHEMA = [sales]",
+		},
+		{
+			MssqlUserResourceModel{Name: types.StringValue("app"), DefaultSchema: types.StringUnknown()},
+			"CREATE USER [app] WITHOUT LOGIN",
+		},
+	}
+	for _, tc := range cases {
+		if got := createUserStatement(&tc.data); got != tc.want {
+			t.Errorf("createUserStatement() = %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -5,7 +5,6 @@ package provider
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"os"
 	"strconv"
@@ -34,11 +33,31 @@ func New(version string) func() provider.Provider {
 }
 
 type mssqlProviderModel struct {
-	Host      types.String `tfsdk:"host"`
-	User      types.String `tfsdk:"user"`
-	Password  types.String `tfsdk:"password"`
-	Port      types.Int32  `tfsdk:"port"`
-	DefaultDb types.String `tfsdk:"default_db"`
+	Host      types.String    `tfsdk:"host"`
+	User      types.String    `tfsdk:"user"`
+	Password  types.String    `tfsdk:"password"`
+	Port      types.Int32     `tfsdk:"port"`
+	DefaultDb types.String    `tfsdk:"default_db"`
+	AzureAuth *azureAuthModel `tfsdk:"azure_auth"`
+	TLS       *tlsModel       `tfsdk:"tls"`
+}
+
+type tlsModel struct {
+	Encrypt                types.String `tfsdk:"encrypt"`
+	TrustServerCertificate types.Bool   `tfsdk:"trust_server_certificate"`
+	CertificatePath        types.String `tfsdk:"certificate_path"`
+	HostNameInCertificate  types.String `tfsdk:"host_name_in_certificate"`
+}
+
+type azureAuthModel struct {
+	Mode                types.String `tfsdk:"mode"`
+	TenantId            types.String `tfsdk:"tenant_id"`
+	ClientId            types.String `tfsdk:"client_id"`
+	ClientSecret        types.String `tfsdk:"client_secret"`
+	CertificatePath     types.String `tfsdk:"certificate_path"`
+	CertificatePassword types.String `tfsdk:"certificate_password"`
+	ResourceId          types.String `tfsdk:"resource_id"`
+	AccessToken         types.String `tfsdk:"access_token"`
 }
 
 // mssqlProvider is the provider implementation.
@@ -46,7 +65,6 @@ type mssqlProvider struct {
 	// version is set to the provider version on release, "dev" when the
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
-	client  *sql.DB
 	version string
 }
 
@@ -79,6 +97,70 @@ func (p *mssqlProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"azure_auth": schema.SingleNestedBlock{
+				MarkdownDescription: "Azure AD / Entra ID authentication for Azure SQL Database and Managed Instance. " +
+					"When set, `user` and `password` are ignored.",
+				Attributes: map[string]schema.Attribute{
+					"mode": schema.StringAttribute{
+						MarkdownDescription: "Authentication mode: `client_secret`, `client_certificate`, `managed_identity` or `access_token`.",
+						Required:            true,
+					},
+					"tenant_id": schema.StringAttribute{
+						MarkdownDescription: "Tenant ID of the service principal. Defaults to the tenant advertised by the server.",
+						Optional:            true,
+					},
+					"client_id": schema.StringAttribute{
+						MarkdownDescription: "Client ID of the service principal, or of the user-assigned managed identity.",
+						Optional:            true,
+					},
+					"client_secret": schema.StringAttribute{
+						MarkdownDescription: "Client secret of the service principal.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"certificate_path": schema.StringAttribute{
+						MarkdownDescription: "Path to a PFX or PEM file holding the service principal certificate and private key.",
+						Optional:            true,
+					},
+					"certificate_password": schema.StringAttribute{
+						MarkdownDescription: "Password protecting the certificate file.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"resource_id": schema.StringAttribute{
+						MarkdownDescription: "Resource ID of a user-assigned managed identity. Takes precedence over `client_id`.",
+						Optional:            true,
+					},
+					"access_token": schema.StringAttribute{
+						MarkdownDescription: "Pre-acquired access token for the `https://database.windows.net/` resource.",
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+			"tls": schema.SingleNestedBlock{
+				MarkdownDescription: "Encryption settings for the connection to the server.",
+				Attributes: map[string]schema.Attribute{
+					"encrypt": schema.StringAttribute{
+						MarkdownDescription: "Encryption mode: `strict` (TDS 8.0), `true`, `false` (login packet only) or `disable`.",
+						Optional:            true,
+					},
+					"trust_server_certificate": schema.BoolAttribute{
+						MarkdownDescription: "Skip validation of the server certificate. Only use this with self-signed certificates in development.",
+						Optional:            true,
+					},
+					"certificate_path": schema.StringAttribute{
+						MarkdownDescription: "Path to a PEM encoded CA bundle used to validate the server certificate.",
+						Optional:            true,
+					},
+					"host_name_in_certificate": schema.StringAttribute{
+						MarkdownDescription: "Host name expected in the server certificate, when it differs from `host`.",
+						Optional:            true,
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -91,41 +173,21 @@ func (p *mssqlProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	// If practitioner provided a configuration value for any of the
-	// attributes, it must be a known value.
-
-	if config.Host.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("host"),
-			"Unknown mssql host",
-			"The provider cannot create connection to the server as there is unknown configuration value from mssql provider host",
-		)
-	}
-	if config.User.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("host"),
-			"Unknown mssql user",
-			"The provider cannot create connection to the server as there is unknown configuration value from mssql provider user",
-		)
-	}
-
-	if config.Password.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("host"),
-			"Unknown mssql password",
-			"The provider cannot create connection to the server as there is unknown configuration value from mssql provider user",
-		)
-	}
-
-	if config.DefaultDb.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("host"),
-			"Unknown mssql default_db",
-			"The provider cannot create connection to the server as there is unknown configuration value from mssql provider default_db",
-		)
-	}
-
-	if resp.Diagnostics.HasError() {
+	// Configuration values may be unknown during plan when they come from
+	// resources that have not been created yet, for example a new server.
+	// Defer the resources until apply when Terraform supports it, otherwise
+	// hand out a client that reports the problem only if a resource actually
+	// needs the server during this run.
+	if !req.Config.Raw.IsFullyKnown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{
+				Reason: provider.DeferredReasonProviderConfigUnknown,
+			}
+			return
+		}
+		client := &mssqlClient{unknown: true}
+		resp.DataSourceData = client
+		resp.ResourceData = client
 		return
 	}
 
@@ -174,7 +236,36 @@ func (p *mssqlProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
-	if user == "" {
+	var azure *azureAuthConfig
+	if config.AzureAuth != nil {
+		azure = &azureAuthConfig{
+			Mode:                config.AzureAuth.Mode.ValueString(),
+			TenantId:            config.AzureAuth.TenantId.ValueString(),
+			ClientId:            config.AzureAuth.ClientId.ValueString(),
+			ClientSecret:        config.AzureAuth.ClientSecret.ValueString(),
+			CertificatePath:     config.AzureAuth.CertificatePath.ValueString(),
+			CertificatePassword: config.AzureAuth.CertificatePassword.ValueString(),
+			ResourceId:          config.AzureAuth.ResourceId.ValueString(),
+			AccessToken:         config.AzureAuth.AccessToken.ValueString(),
+		}
+	}
+
+	var tls *tlsConfig
+	if config.TLS != nil {
+		tls = &tlsConfig{
+			Encrypt:               config.TLS.Encrypt.ValueString(),
+			CertificatePath:       config.TLS.CertificatePath.ValueString(),
+			HostNameInCertificate: config.TLS.HostNameInCertificate.ValueString(),
+		}
+		if !config.TLS.TrustServerCertificate.IsNull() {
+			trust := config.TLS.TrustServerCertificate.ValueBool()
+			tls.TrustServerCertificate = &trust
+		}
+	}
+
+	// SQL authentication needs a user and password, Azure AD authentication
+	// takes its credentials from the azure_auth block instead.
+	if azure == nil && user == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("user"),
 			"Missing mssql User",
@@ -182,7 +273,7 @@ func (p *mssqlProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
-	if password == "" {
+	if azure == nil && password == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("password"),
 			"Missing mssql Password",
@@ -198,24 +289,29 @@ func (p *mssqlProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		port = 1433
 	}
 
-	connString := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=master",
-		user,
-		password,
-		host,
-		port,
-	)
-
-	client, err := sql.Open("sqlserver", connString)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to ping DB", err.Error())
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	if err := client.PingContext(ctx); err != nil {
+
+	conn := connectionConfig{
+		Host:     host,
+		Port:     port,
+		User:     user,
+		Password: password,
+		Azure:    azure,
+		TLS:      tls,
+	}
+	if _, err := conn.connString("master"); err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to connecte to SQL Server",
-			fmt.Sprintf("Ping failed: %s", err.Error()),
+			"Invalid mssql connection configuration",
+			err.Error(),
 		)
+		return
 	}
+
+	// The connection is opened by the first resource or data source that
+	// needs it.
+	client := newMssqlClient(conn)
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
@@ -224,6 +320,7 @@ func (p *mssqlProvider) Configure(ctx context.Context, req provider.ConfigureReq
 func (p *mssqlProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewMssqlDataSource,
+		NewMssqlLoginDataSource,
 	}
 }
 
@@ -235,5 +332,13 @@ func (p *mssqlProvider) Resources(_ context.Context) []func() resource.Resource
 		NewMssqlUserResource,
 		NewMssqlRoleResource,
 		NewMssqlRoleAssignmentResource,
+		NewMssqlFilegroupResource,
+		NewMssqlDatabaseSnapshotResource,
+		NewMssqlDatabaseScopedConfigurationResource,
+		NewMssqlChangeTrackingTableResource,
+		NewMssqlCdcTableResource,
+		NewMssqlMasterKeyResource,
+		NewMssqlCertificateResource,
+		NewMssqlDatabaseEncryptionKeyResource,
 	}
 }
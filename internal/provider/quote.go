@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DDL statements cannot take parameters for object names or most option
+// values, so every name or literal interpolated into T-SQL must go through
+// one of the helpers below. Values that can be parameterized, such as the
+// filters of catalog queries, should be passed as @p arguments instead.
+
+// quoteIdentifier delimits a SQL Server identifier the same way QUOTENAME
+// does, wrapping it in brackets and doubling any closing bracket.
+func quoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// quoteString returns value as a Unicode string literal, doubling any single
+// quote.
+func quoteString(value string) string {
+	return "N'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// keywordPattern matches option values that are emitted unquoted, such as
+// collation names or ON/OFF style settings.
+var keywordPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validateKeyword returns an error if value cannot be emitted into a
+// statement unquoted. what describes the value for the error message.
+func validateKeyword(what, value string) error {
+	if !keywordPattern.MatchString(value) {
+		return fmt.Errorf("invalid %s %q: only letters, digits and underscores are allowed", what, value)
+	}
+	return nil
+}
+
+// qualifiedName returns a delimited two-part name such as [dbo].[orders],
+// usable in statements and as the argument of OBJECT_ID.
+func qualifiedName(schema, name string) string {
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"plain":                       "[plain]",
+		"with space":                  "[with space]",
+		"close]bracket":               "[close]]bracket]",
+		"]]":                          "[]]]]]",
+		"[open":                       "[[open]",
+		"quote'name":                  "[quote'name]",
+		"x]; DROP DATABASE master;--": "[x]]; DROP DATABASE master;--]",
+		"":                            "[]",
+		"ünïcødé":                     "[ünïcødé]",
+	}
+	for in, want := range cases {
+		if got := quoteIdentifier(in); got != want {
+			t.Errorf("quoteIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	cases := map[string]string{
+		"secret":               "N'secret'",
+		"it's":                 "N'it''s'",
+		"''":                   "N''''''",
+		"x'; DROP LOGIN sa;--": "N'x''; DROP LOGIN sa;--'",
+		"bracket]":             "N'bracket]'",
+		"":                     "N''",
+		"pässwörd":             "N'pässwörd'",
+		"line\nbreak":          "N'line\nbreak'",
+		`back\slash`:           `N'back\slash'`,
+		"N'already quoted'":    "N'N''already quoted'''",
+	}
+	for in, want := range cases {
+		if got := quoteString(in); got != want {
+			t.Errorf("quoteString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateKeyword(t *testing.T) {
+	valid := []string{"SQL_Latin1_General_CP1_CI_AS", "Latin1_General_100_CI_AS_SC_UTF8", "ON", "0"}
+	for _, v := range valid {
+		if err := validateKeyword("collation", v); err != nil {
+			t.Errorf("validateKeyword(%q) returned unexpected error: %s", v, err)
+		}
+	}
+
+	invalid := []string{"", "Latin1 General", "x; DROP DATABASE master", "a]b", "a'b", "a--", "a/*b*/"}
+	for _, v := range invalid {
+		if err := validateKeyword("collation", v); err == nil {
+			t.Errorf("validateKeyword(%q) expected an error", v)
+		}
+	}
+}
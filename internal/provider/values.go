@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// keepCase returns prior when it matches actual case-insensitively, so
+// names and keywords the server normalizes do not show up as drift.
+func keepCase(prior types.String, actual string) types.String {
+	if !prior.IsNull() && !prior.IsUnknown() && strings.EqualFold(prior.ValueString(), actual) {
+		return prior
+	}
+	return types.StringValue(actual)
+}
+
+// known reports whether an optional and computed value was set in the
+// configuration or carried over from state.
+func known(v interface {
+	IsNull() bool
+	IsUnknown() bool
+}) bool {
+	return !v.IsNull() && !v.IsUnknown()
+}
+
+// onOff renders a boolean as the ON/OFF keyword used by SET options.
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// nullString returns the value of v as a query parameter, or nil when it is
+// not known so the server applies its default.
+func nullString(v types.String) any {
+	if !known(v) {
+		return nil
+	}
+	return v.ValueString()
+}
+
+// nullBool is nullString for booleans.
+func nullBool(v types.Bool) any {
+	if !known(v) {
+		return nil
+	}
+	return v.ValueBool()
+}